@@ -1,7 +1,58 @@
 package dataurl
 
 
+import (
+	"bytes"
+	"fmt"
+)
+
+
+const (
+	badRequestMessagePrefix = "Bad Request: "
+)
+
+
 type BadRequestComplainer interface {
 	error
 	BadRequestComplainer()
 }
+
+
+// internalBadRequestComplainer is the underlying implementation, in this library, for a
+// BadRequestComplainer that is not one of the more specific kinds of BadRequestComplainer.
+// For example, a value of the wrong type passed to a template function.
+type internalBadRequestComplainer struct {
+	msg string
+}
+
+
+// newBadRequestComplainer creates a new internalBadRequestComplainer (struct) and
+// returns it as a BadRequestComplainer (interface).
+func newBadRequestComplainer(format string, a ...interface{}) BadRequestComplainer {
+	msg := fmt.Sprintf(format, a...)
+
+	err := internalBadRequestComplainer{
+		msg:msg,
+	}
+
+	return &err
+}
+
+
+// Error method is necessary to satisfy the 'error' interface (and the BadRequestComplainer
+// interface).
+func (err *internalBadRequestComplainer) Error() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString(badRequestMessagePrefix)
+	buffer.WriteString(err.msg)
+
+	return buffer.String()
+}
+
+
+// BadRequestComplainer method is necessary to satisfy the 'BadRequestComplainer' interface.
+// It exists to make this error type detectable in a Go type-switch.
+func (err *internalBadRequestComplainer) BadRequestComplainer() {
+	// Nothing here.
+}
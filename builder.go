@@ -0,0 +1,179 @@
+package dataurl
+
+
+import (
+	"bytes"
+	"strings"
+)
+
+
+// Builder is used to build a data URL (or a Parcel) a piece at a time.
+//
+// The media type, its parameters (including the charset), and the encoding can
+// each be set separately. And the contents are written into it, like into a
+// bytes.Buffer. (A Builder is an io.Writer.)
+//
+// The zero value of Builder is ready to use; with the default media type
+// ("text/plain;charset=US-ASCII") and no contents.
+//
+// Example usage:
+//
+//	var builder dataurl.Builder
+//
+//	builder.SetMediaType("image/svg+xml")
+//	builder.SetCharset("utf-8")
+//	builder.SetEncoding(dataurl.EncodingPercent)
+//	builder.WriteString(`<svg xmlns="http://www.w3.org/2000/svg"/>`)
+//
+//	dataURL, err := builder.DataURL()
+//	if nil != err {
+//		//@TODO
+//	}
+//
+// If SetEncoding is not called, then whichever encoding results in the shorter
+// data URL is used.
+type Builder struct {
+	mimeType    string
+	params      map[string]string
+	encoding    Encoding
+	encodingSet bool
+	buffer      bytes.Buffer
+}
+
+
+// SetMediaType sets the media type (ex: "image/png"); replacing the media type and
+// any parameters set before.
+//
+// 'mediaType' may include parameters (ex: "text/html;charset=utf-8"). If it is the
+// empty string, then the default media type is used.
+func (builder *Builder) SetMediaType(mediaType string) error {
+	if "" == mediaType {
+		builder.mimeType = ""
+		builder.params = nil
+		return nil
+	}
+
+	mimeType, params, err := parseMediaType(mediaType)
+	if nil != err {
+		return err
+	}
+
+	builder.mimeType = mimeType
+	builder.params = params
+	return nil
+}
+
+
+// SetParameter sets the media type parameter 'name' to 'value'.
+//
+// For example:
+//
+//	builder.SetParameter("name", "logo.png")
+func (builder *Builder) SetParameter(name string, value string) {
+	if nil == builder.params {
+		builder.params = map[string]string{}
+	}
+
+	builder.params[strings.ToLower(name)] = value
+}
+
+
+// SetCharset sets the "charset" parameter of the media type.
+func (builder *Builder) SetCharset(charset string) {
+	builder.SetParameter("charset", charset)
+}
+
+
+// SetEncoding sets which encoding (base64 or percent encoding) is used for the contents.
+func (builder *Builder) SetEncoding(encoding Encoding) {
+	builder.encoding = encoding
+	builder.encodingSet = true
+}
+
+
+// Write appends 'p' to the contents. It always returns len(p), nil.
+func (builder *Builder) Write(p []byte) (int, error) {
+	return builder.buffer.Write(p)
+}
+
+
+// WriteString appends 's' to the contents. It always returns len(s), nil.
+func (builder *Builder) WriteString(s string) (int, error) {
+	return builder.buffer.WriteString(s)
+}
+
+
+// WriteByte appends 'b' to the contents. It always returns nil.
+func (builder *Builder) WriteByte(b byte) error {
+	return builder.buffer.WriteByte(b)
+}
+
+
+// Len returns the length of the contents written so far, in bytes.
+func (builder *Builder) Len() int {
+	return builder.buffer.Len()
+}
+
+
+// Reset removes the contents; but keeps the media type, its parameters, and the encoding.
+func (builder *Builder) Reset() {
+	builder.buffer.Reset()
+}
+
+
+// mediaType returns the media type, formatted so that it can be put into a data URL.
+func (builder *Builder) mediaType() (string, error) {
+	mimeType := builder.mimeType
+	if "" == mimeType {
+		if 0 == len(builder.params) {
+			return "", nil
+		}
+		mimeType = "text/plain"
+	}
+
+	return formatMediaTypeParams(mimeType, builder.params)
+}
+
+
+// DataURL returns the data URL that has been built.
+func (builder *Builder) DataURL() (string, error) {
+	mediaType, err := builder.mediaType()
+	if nil != err {
+		return "", err
+	}
+
+	data := builder.buffer.Bytes()
+
+	encoding := builder.encoding
+	if !builder.encodingSet {
+		encoding = ShortestEncoding(data)
+	}
+
+	return encodeFormatted(mediaType, data, encoding)
+}
+
+
+// String returns the data URL that has been built; or the empty string if there was
+// an error. (Use DataURL to get the error.)
+func (builder *Builder) String() string {
+	dataURL, err := builder.DataURL()
+	if nil != err {
+		return ""
+	}
+
+	return dataURL
+}
+
+
+// Parcel returns a Parcel with the media type and contents that have been built.
+//
+// The contents are copied; so writing more into the Builder does not affect the
+// returned Parcel.
+func (builder *Builder) Parcel() (Parcel, error) {
+	mediaType, err := builder.mediaType()
+	if nil != err {
+		return nil, err
+	}
+
+	return NewParcel(mediaType, builder.buffer.Bytes())
+}
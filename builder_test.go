@@ -0,0 +1,126 @@
+package dataurl
+
+
+import (
+	"testing"
+)
+
+
+func TestBuilder(t *testing.T) {
+
+	tests := []struct{
+		Build    func(*Builder)
+		Expected string
+	}{
+		{
+			Build:    func(builder *Builder) {},
+			Expected: `data:,`,
+		},
+		{
+			Build: func(builder *Builder) {
+				builder.WriteString("Hello world!")
+			},
+			Expected: `data:,Hello%20world!`,
+		},
+		{
+			Build: func(builder *Builder) {
+				builder.SetCharset("utf-8")
+				builder.WriteString("Hello")
+			},
+			Expected: `data:text/plain;charset=utf-8,Hello`,
+		},
+		{
+			Build: func(builder *Builder) {
+				builder.SetMediaType("IMAGE/SVG+XML")
+				builder.SetEncoding(EncodingBase64)
+				builder.WriteString("<svg/>")
+			},
+			Expected: `data:image/svg+xml;base64,PHN2Zy8+`,
+		},
+		{
+			Build: func(builder *Builder) {
+				builder.SetMediaType("text/html; charset=utf-8")
+				builder.SetParameter("Name", "a/b.html")
+				builder.SetEncoding(EncodingPercent)
+				builder.Write([]byte("<p>"))
+				builder.WriteByte('!')
+			},
+			Expected: `data:text/html;charset=utf-8;name="a/b.html",%3Cp%3E!`,
+		},
+		{
+			Build: func(builder *Builder) {
+				builder.SetMediaType("application/octet-stream")
+				builder.Write([]byte{0x00, 0xff, 0x00, 0xff, 0x00, 0xff, 0x00, 0xff})
+			},
+			Expected: `data:application/octet-stream;base64,AP8A/wD/AP8=`,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		var builder Builder
+		test.Build(&builder)
+
+		actual, err := builder.DataURL()
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if expected := test.Expected; expected != actual {
+			t.Errorf("For test #%d, expected data URL to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+		if expected, actual := test.Expected, builder.String(); expected != actual {
+			t.Errorf("For test #%d, expected String() to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+
+		parcel, err := builder.Parcel()
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error when creating a parcel, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		parsed := MustParse(actual)
+		if expected, actual := parsed.String(), parcel.String(); expected != actual {
+			t.Errorf("For test #%d, expected contents to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+	}
+}
+
+
+func TestBuilderBadMediaType(t *testing.T) {
+	var builder Builder
+
+	err := builder.SetMediaType("not a media type")
+	if nil == err {
+		t.Fatalf("Expected an error, but did not actually get one.")
+	}
+	if _, ok := err.(BadMediaTypeComplainer); !ok {
+		t.Errorf("Expected a BadMediaTypeComplainer, but actually got %T.", err)
+	}
+}
+
+
+// A parameter value with a comma, a double quote or whitespace in it cannot be put into a data URL.
+func TestBuilderBadParameter(t *testing.T) {
+
+	for testNumber, value := range []string{"a b.html", "a,b.html", `a"b.html`} {
+		var builder Builder
+		builder.SetMediaType("text/html")
+		builder.SetParameter("name", value)
+		builder.WriteString("<p>")
+
+		_, err := builder.DataURL()
+		if nil == err {
+			t.Errorf("For test #%d, expected an error, but actually did not get one.\nValue: %q", testNumber, value)
+			continue
+		}
+		if _, ok := err.(BadMediaTypeComplainer); !ok {
+			t.Errorf("For test #%d, expected the error to be a BadMediaTypeComplainer, but actually was %T: %v", testNumber, err, err)
+			continue
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package dataurl
+
+
+import (
+	"bytes"
+	"strings"
+)
+
+
+// Canonicalize returns the canonical form of the data URL 'dataURL'.
+//
+// The same contents, with the same media type, can be written as a data URL in many
+// different ways. For example, all of these are the same:
+//
+//	data:,A
+//	data:text/plain,A
+//	data:TEXT/PLAIN;CHARSET=us-ascii,%41
+//	data:text/plain;charset=US-ASCII;base64,QQ==
+//
+// Canonicalize returns just one of those, for all of them: "data:,A".
+//
+// The canonical form is:
+//
+//   - The type, the subtype, the parameter names, and the value of the "charset"
+//     parameter are lower-cased. (The values of other parameters are kept as is.)
+//   - The parameters are sorted by name, and there is no whitespace.
+//   - Whatever a data URL implies by default is left out. So "charset=US-ASCII" is
+//     left out, and "text/plain" is left out (leaving just its parameters, if any).
+//   - The contents are percent encoded (escaping only what must be escaped) if that
+//     is shorter than base64 encoding; otherwise they are base64 encoded.
+//
+// This makes the canonical form useful as a key when de-duplicating or caching.
+//
+// Example usage:
+//
+//	canonical, err := dataurl.Canonicalize("data:TEXT/PLAIN;CHARSET=UTF-8;base64,SGVsbG8=")
+//	if nil != err {
+//		//@TODO
+//	}
+//
+//	fmt.Println(canonical) // data:;charset=utf-8,Hello
+func Canonicalize(dataURL string) (string, error) {
+	parcel, err := parse(dataURL)
+	if nil != err {
+		return "", err
+	}
+
+	mediaType, err := canonicalMediaType(parcel.mediaType)
+	if nil != err {
+		return "", err
+	}
+
+	data := UnsafeBytes(parcel)
+
+	return encodeFormatted(mediaType, data, ShortestEncoding(data))
+}
+
+
+// Equal returns whether the parcels 'a' and 'b' have the same contents, and the same media type.
+//
+// Media types are compared by what they mean; not by how they are written. So, for example,
+// "IMAGE/PNG" and "image/png;charset=US-ASCII" are the same media type. And so are
+// "text/html;charset=UTF-8;q=1" and "text/html; q=1; charset=utf-8".
+func Equal(a, b Parcel) bool {
+	// Parcels are not compared with ==; since that panics if a Parcel is not comparable (ex: if
+	// it is a struct with a slice in it).
+	if nil == a || nil == b {
+		return nil == a && nil == b
+	}
+
+	if !bytes.Equal(UnsafeBytes(a), UnsafeBytes(b)) {
+		return false
+	}
+
+	return equalMediaTypes(a.MediaType(), b.MediaType())
+}
+
+
+// EquivalentURLs returns whether the data URLs 'a' and 'b' have the same contents and the
+// same media type. (See dataurl.Equal().)
+//
+// An error is returned if either 'a' or 'b' is not a valid data URL.
+//
+// Example usage:
+//
+//	equivalent, err := dataurl.EquivalentURLs("data:,A", "data:text/plain;charset=US-ASCII;base64,QQ==")
+//	if nil != err {
+//		//@TODO
+//	}
+//
+//	fmt.Println(equivalent) // true
+func EquivalentURLs(a, b string) (bool, error) {
+	parcelA, err := parse(a)
+	if nil != err {
+		return false, err
+	}
+
+	parcelB, err := parse(b)
+	if nil != err {
+		return false, err
+	}
+
+	return Equal(parcelA, parcelB), nil
+}
+
+
+// equalMediaTypes returns whether 'a' and 'b' are the same media type.
+//
+// If either could not be parsed, then they are only the same if they are written the same.
+func equalMediaTypes(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	canonicalA, err := canonicalMediaType(a)
+	if nil != err {
+		return false
+	}
+
+	canonicalB, err := canonicalMediaType(b)
+	if nil != err {
+		return false
+	}
+
+	return canonicalA == canonicalB
+}
+
+
+// canonicalMediaType returns the canonical form of 'mediaType'. (See Canonicalize.)
+func canonicalMediaType(mediaType string) (string, error) {
+	if "" == mediaType {
+		return "", nil
+	}
+
+	formatted, err := rewriteMediaType(mediaType, func(mimeType string, params map[string]string) string {
+		if charset, ok := params["charset"]; ok {
+			params["charset"] = strings.ToLower(charset)
+		}
+		return mimeType
+	})
+	if nil != err {
+		return "", err
+	}
+
+	return minimizeMediaType(formatted)
+}
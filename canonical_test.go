@@ -0,0 +1,217 @@
+package dataurl
+
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+
+func TestCanonicalize(t *testing.T) {
+
+	tests := []struct{
+		DataURLs []string
+		Expected string
+	}{
+		{
+			DataURLs: []string{
+				`data:,A`,
+				`data:text/plain,A`,
+				`data:;charset=US-ASCII,A`,
+				`data:TEXT/PLAIN;CHARSET=us-ascii,%41`,
+				`data:text/plain;charset=US-ASCII;base64,QQ==`,
+			},
+			Expected: `data:,A`,
+		},
+		{
+			DataURLs: []string{
+				`data:,`,
+				`data:;base64,`,
+				`data:text/plain;charset=US-ASCII,`,
+			},
+			Expected: `data:,`,
+		},
+		{
+			DataURLs: []string{
+				`data:text/html;charset=UTF-8;q=1,%3Cp%3EHi%3C/p%3E`,
+				`data:TEXT/HTML;q=1;charset=utf-8,<p>Hi</p>`,
+				`data:text/html;charset=utf-8;q=1;base64,PHA+SGk8L3A+`,
+			},
+			Expected: `data:text/html;charset=utf-8;q=1,%3Cp%3EHi%3C/p%3E`,
+		},
+		{
+			DataURLs: []string{
+				`data:;charset=UTF-8,Hello`,
+				`data:text/plain;charset=utf-8;base64,SGVsbG8=`,
+			},
+			Expected: `data:;charset=utf-8,Hello`,
+		},
+		{
+			DataURLs: []string{
+				`data:IMAGE/PNG;base64,iVBORw0KGgo=`,
+				`data:image/png,%89PNG%0D%0A%1A%0A`,
+			},
+			Expected: `data:image/png,%89PNG%0D%0A%1A%0A`,
+		},
+		{
+			DataURLs: []string{
+				`data:Application/Octet-Stream,%00%FF%00%FF%00%FF%00%FF`,
+				`data:application/octet-stream;base64,AP8A/wD/AP8=`,
+			},
+			Expected: `data:application/octet-stream;base64,AP8A/wD/AP8=`,
+		},
+		{
+			DataURLs: []string{
+				`data:text/plain;name=Report.TXT,x`,
+			},
+			Expected: `data:;name=Report.TXT,x`,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		for _, dataURL := range test.DataURLs {
+			actual, err := Canonicalize(dataURL)
+			if nil != err {
+				t.Errorf("For test #%d and data URL %q, did not expect an error, but actually got one: %v", testNumber, dataURL, err)
+				continue
+			}
+
+			if expected := test.Expected; expected != actual {
+				t.Errorf("For test #%d and data URL %q, expected canonical form to be %q, but actually was %q.", testNumber, dataURL, expected, actual)
+				continue
+			}
+
+			// The canonical form of the canonical form must be itself.
+			again, err := Canonicalize(actual)
+			if nil != err {
+				t.Errorf("For test #%d and data URL %q, did not expect an error, but actually got one: %v", testNumber, dataURL, err)
+				continue
+			}
+			if expected, actual := actual, again; expected != actual {
+				t.Errorf("For test #%d and data URL %q, expected canonicalizing again to give %q, but actually was %q.", testNumber, dataURL, expected, actual)
+				continue
+			}
+
+			for _, other := range test.DataURLs {
+				equivalent, err := EquivalentURLs(dataURL, other)
+				if nil != err {
+					t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+					continue
+				}
+				if !equivalent {
+					t.Errorf("For test #%d, expected %q and %q to be equivalent, but they were not.", testNumber, dataURL, other)
+					continue
+				}
+			}
+		}
+	}
+}
+
+
+func TestEquivalentURLsNotEquivalent(t *testing.T) {
+
+	tests := []struct{
+		A string
+		B string
+	}{
+		{
+			A: `data:,A`,
+			B: `data:,a`,
+		},
+		{
+			A: `data:,A`,
+			B: `data:;charset=utf-8,A`,
+		},
+		{
+			A: `data:image/png,A`,
+			B: `data:image/jpeg,A`,
+		},
+		{
+			A: `data:text/plain;name=a,A`,
+			B: `data:text/plain;name=A,A`,
+		},
+		{
+			A: `data:,A`,
+			B: `data:,A%00`,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		equivalent, err := EquivalentURLs(test.A, test.B)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if equivalent {
+			t.Errorf("For test #%d, did not expect %q and %q to be equivalent, but they were.", testNumber, test.A, test.B)
+			continue
+		}
+	}
+
+
+	if _, err := EquivalentURLs(`data:,A`, `http://example.com/`); nil == err {
+		t.Errorf("Expected an error for something that is not a data URL, but did not actually get one.")
+	}
+}
+
+
+func TestEqual(t *testing.T) {
+	a := MustParse(`data:IMAGE/SVG+XML;charset=UTF-8,%3Csvg/%3E`)
+	b, err := NewParcel("image/svg+xml;charset=utf-8", []byte(`<svg/>`))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if !Equal(a, b) {
+		t.Errorf("Expected the parcels to be equal, but they were not.")
+	}
+	if !Equal(a, a) {
+		t.Errorf("Expected a parcel to be equal to itself, but it was not.")
+	}
+	if Equal(a, nil) {
+		t.Errorf("Did not expect a parcel to be equal to nil, but it was.")
+	}
+}
+
+
+// sliceParcel is a Parcel that is not comparable (with ==); since it has a slice in it.
+type sliceParcel struct {
+	content   []byte
+	mediaType string
+}
+
+func (parcel sliceParcel) Bytes() []byte     { return append([]byte(nil), parcel.content...) }
+func (parcel sliceParcel) Reader() io.Reader { return bytes.NewReader(parcel.content) }
+func (parcel sliceParcel) Runes() []rune     { return []rune(string(parcel.content)) }
+func (parcel sliceParcel) String() string    { return string(parcel.content) }
+func (parcel sliceParcel) MediaType() string { return parcel.mediaType }
+
+
+func TestEqualNotComparable(t *testing.T) {
+	a := sliceParcel{content: []byte("<svg/>"), mediaType: "image/svg+xml"}
+	b := sliceParcel{content: []byte("<svg/>"), mediaType: "IMAGE/SVG+XML"}
+	c := sliceParcel{content: []byte("<svg></svg>"), mediaType: "image/svg+xml"}
+
+	if !Equal(a, a) {
+		t.Errorf("Expected a parcel to be equal to itself, but it was not.")
+	}
+	if !Equal(a, b) {
+		t.Errorf("Expected the parcels to be equal, but they were not.")
+	}
+	if !Equal(a, MustParse(`data:image/svg+xml,%3Csvg/%3E`)) {
+		t.Errorf("Expected the parcels to be equal, but they were not.")
+	}
+	if Equal(a, c) {
+		t.Errorf("Did not expect the parcels to be equal, but they were.")
+	}
+	if !Equal(nil, nil) {
+		t.Errorf("Expected nil to be equal to nil, but it was not.")
+	}
+	if Equal(nil, a) {
+		t.Errorf("Did not expect nil to be equal to a parcel, but it was.")
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+func decodeCommand(args []string) int {
+	flags := flag.NewFlagSet("decode", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	var output string
+
+	flags.StringVar(&output, "o", "", "file to write the contents to (STDOUT if not given)")
+
+	if err := flags.Parse(args); nil != err || 1 < flags.NArg() {
+		fmt.Fprintln(stderr, "usage: dataurl decode [-o file] [data-url]")
+		return exitUsage
+	}
+
+	dataURL, err := readDataURL(flags.Args())
+	if nil != err {
+		return fail("decode", err)
+	}
+
+	parcel, err := dataurl.Parse(dataURL)
+	if nil != err {
+		return fail("decode", err)
+	}
+
+	if "" == output || "-" == output {
+		if _, err := stdout.Write(parcel.Bytes()); nil != err {
+			return fail("decode", err)
+		}
+		return exitOK
+	}
+
+	if err := os.WriteFile(output, parcel.Bytes(), 0644); nil != err {
+		return fail("decode", err)
+	}
+
+	return exitOK
+}
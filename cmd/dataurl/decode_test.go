@@ -0,0 +1,99 @@
+package main
+
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+
+func TestDecodeCommand(t *testing.T) {
+
+	tests := []struct{
+		Args           []string
+		Input          string
+		ExpectedCode   int
+		ExpectedStdout string
+	}{
+		{
+			Args:           []string{"decode", "data:,Hello%20world!"},
+			Input:          "",
+			ExpectedCode:   exitOK,
+			ExpectedStdout: "Hello world!",
+		},
+		{
+			// The data URL is read from STDIN.
+			Args:           []string{"decode"},
+			Input:          "data:;base64,SGk=\n",
+			ExpectedCode:   exitOK,
+			ExpectedStdout: "Hi",
+		},
+		{
+			Args:           []string{"decode", "http://example.com/"},
+			Input:          "",
+			ExpectedCode:   exitNotADataURL,
+			ExpectedStdout: "",
+		},
+		{
+			Args:           []string{"decode", "data:text/plain"},
+			Input:          "",
+			ExpectedCode:   exitSyntaxError,
+			ExpectedStdout: "",
+		},
+		{
+			Args:           []string{"decode", "data:;base64,!!!!"},
+			Input:          "",
+			ExpectedCode:   exitSyntaxError,
+			ExpectedStdout: "",
+		},
+		{
+			Args:           []string{"decode", "data:bad media type,x"},
+			Input:          "",
+			ExpectedCode:   exitBadMediaType,
+			ExpectedStdout: "",
+		},
+		{
+			Args:           []string{"decode", "data:,a", "data:,b"},
+			Input:          "",
+			ExpectedCode:   exitUsage,
+			ExpectedStdout: "",
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		code, stdout, _ := runWith(test.Input, test.Args...)
+
+		if expected, actual := test.ExpectedCode, code; expected != actual {
+			t.Errorf("For test #%d, expected exit code %d, but actually got %d.", testNumber, expected, actual)
+			continue
+		}
+		if expected, actual := test.ExpectedStdout, stdout; expected != actual {
+			t.Errorf("For test #%d, expected STDOUT %q, but actually got %q.", testNumber, expected, actual)
+			continue
+		}
+	}
+}
+
+
+func TestDecodeCommandOutputFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "hello.txt")
+
+	code, stdout, _ := runWith("", "decode", "-o", name, "data:,Hello%20world!")
+
+	if expected, actual := exitOK, code; expected != actual {
+		t.Errorf("Expected exit code %d, but actually got %d.", expected, actual)
+	}
+	if expected, actual := "", stdout; expected != actual {
+		t.Errorf("Expected STDOUT %q, but actually got %q.", expected, actual)
+	}
+
+	p, err := os.ReadFile(name)
+	if nil != err {
+		t.Fatalf("Could not read file: %s", err)
+	}
+	if expected, actual := "Hello world!", string(p); expected != actual {
+		t.Errorf("Expected the file to contain %q, but actually was %q.", expected, actual)
+	}
+}
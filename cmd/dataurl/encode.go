@@ -0,0 +1,63 @@
+package main
+
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+func encodeCommand(args []string) int {
+	flags := flag.NewFlagSet("encode", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	var mediaType string
+	var percent   bool
+	var shortest  bool
+
+	flags.StringVar(&mediaType, "type", "", "media type of the contents (guessed if not given)")
+	flags.BoolVar(&percent, "percent", false, "percent encode (rather than base64 encode) the contents")
+	flags.BoolVar(&shortest, "shortest", false, "use whichever encoding is shorter, and minimize the media type")
+
+	if err := flags.Parse(args); nil != err {
+		fmt.Fprintln(stderr, "usage: dataurl encode [-type media-type] [-percent | -shortest] [file]")
+		return exitUsage
+	}
+	if 1 < flags.NArg() {
+		fmt.Fprintln(stderr, "usage: dataurl encode [-type media-type] [-percent | -shortest] [file]")
+		return exitUsage
+	}
+
+	name := flags.Arg(0)
+
+	data, err := readInput(name)
+	if nil != err {
+		return fail("encode", err)
+	}
+
+	if "" == mediaType {
+		mediaType = dataurl.GuessMediaType(name, data)
+	}
+
+	var dataURL string
+	if shortest {
+		dataURL, err = dataurl.EncodeShortest(mediaType, data)
+	} else {
+		encoding := dataurl.EncodingBase64
+		if percent {
+			encoding = dataurl.EncodingPercent
+		}
+
+		dataURL, err = dataurl.Encode(mediaType, data, encoding)
+	}
+	if nil != err {
+		return fail("encode", err)
+	}
+
+	fmt.Fprintln(stdout, dataURL)
+
+	return exitOK
+}
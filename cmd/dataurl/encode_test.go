@@ -0,0 +1,94 @@
+package main
+
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+
+func TestEncodeCommand(t *testing.T) {
+
+	tests := []struct{
+		Args           []string
+		Input          string
+		ExpectedCode   int
+		ExpectedStdout string
+	}{
+		{
+			Args:           []string{"encode", "-type", "text/plain", "-percent"},
+			Input:          "Hello world",
+			ExpectedCode:   exitOK,
+			ExpectedStdout: "data:text/plain,Hello%20world\n",
+		},
+		{
+			Args:           []string{"encode", "-type", "text/plain", "-shortest"},
+			Input:          "Hello",
+			ExpectedCode:   exitOK,
+			ExpectedStdout: "data:,Hello\n",
+		},
+		{
+			// The media type is guessed.
+			Args:           []string{"encode"},
+			Input:          "Hello",
+			ExpectedCode:   exitOK,
+			ExpectedStdout: "data:text/plain;charset=utf-8;base64,SGVsbG8=\n",
+		},
+		{
+			Args:           []string{"encode", "-type", "bad type"},
+			Input:          "Hello",
+			ExpectedCode:   exitBadMediaType,
+			ExpectedStdout: "",
+		},
+		{
+			Args:           []string{"encode", filepath.Join(os.TempDir(), "dataurl-does-not-exist")},
+			Input:          "",
+			ExpectedCode:   exitFailure,
+			ExpectedStdout: "",
+		},
+		{
+			Args:           []string{"encode", "a", "b"},
+			Input:          "",
+			ExpectedCode:   exitUsage,
+			ExpectedStdout: "",
+		},
+		{
+			Args:           []string{"encode", "-unknown"},
+			Input:          "",
+			ExpectedCode:   exitUsage,
+			ExpectedStdout: "",
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		code, stdout, _ := runWith(test.Input, test.Args...)
+
+		if expected, actual := test.ExpectedCode, code; expected != actual {
+			t.Errorf("For test #%d, expected exit code %d, but actually got %d.", testNumber, expected, actual)
+			continue
+		}
+		if expected, actual := test.ExpectedStdout, stdout; expected != actual {
+			t.Errorf("For test #%d, expected STDOUT %q, but actually got %q.", testNumber, expected, actual)
+			continue
+		}
+	}
+}
+
+
+func TestEncodeCommandFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "dot.svg")
+	if err := os.WriteFile(name, []byte("<svg/>"), 0644); nil != err {
+		t.Fatalf("Could not write file: %s", err)
+	}
+
+	code, stdout, _ := runWith("", "encode", "-percent", name)
+
+	if expected, actual := exitOK, code; expected != actual {
+		t.Errorf("Expected exit code %d, but actually got %d.", expected, actual)
+	}
+	if expected, actual := "data:image/svg+xml,%3Csvg/%3E\n", stdout; expected != actual {
+		t.Errorf("Expected STDOUT %q, but actually got %q.", expected, actual)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+
+import (
+	"fmt"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+const (
+	exitOK             = 0
+	exitFailure        = 1
+	exitUsage          = 2
+	exitNotADataURL    = 3
+	exitSyntaxError    = 4
+	exitBadMediaType   = 5
+	exitBadRequest     = 6
+	exitInternalError  = 7
+	exitFindings       = 8
+)
+
+
+// exitCode returns the exit code that matches the (complainer) class of 'err'.
+//
+// Note that the more specific complainers come BEFORE the dataurl.BadRequestComplainer
+// case. That is important!
+func exitCode(err error) int {
+	switch err.(type) {
+	case nil:
+		return exitOK
+	case dataurl.NotADataUrlComplainer:
+		return exitNotADataURL
+	case dataurl.SyntaxErrorComplainer:
+		return exitSyntaxError
+	case dataurl.BadMediaTypeComplainer:
+		return exitBadMediaType
+	case dataurl.BadRequestComplainer:
+		return exitBadRequest
+	case dataurl.InternalErrorComplainer:
+		return exitInternalError
+	default:
+		return exitFailure
+	}
+}
+
+
+// fail reports 'err' on STDERR, and returns the matching exit code.
+func fail(command string, err error) int {
+	fmt.Fprintf(stderr, "dataurl %s: %s\n", command, err)
+	return exitCode(err)
+}
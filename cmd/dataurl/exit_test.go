@@ -0,0 +1,75 @@
+package main
+
+
+import (
+	"crypto"
+	"errors"
+	"testing"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+// internalError is a dataurl.InternalErrorComplainer; since the dataurl package does not return one
+// for any input that a test could give it.
+type internalError struct{}
+
+func (internalError) Error() string            { return "Internal Error: test" }
+func (internalError) InternalErrorComplainer() {}
+
+
+// errorOf returns the error, of the values returned by a function.
+func errorOf(_ interface{}, err error) error {
+	return err
+}
+
+
+func TestExitCode(t *testing.T) {
+
+	tests := []struct{
+		Err          error
+		ExpectedCode int
+	}{
+		{
+			Err:          nil,
+			ExpectedCode: exitOK,
+		},
+		{
+			Err:          errors.New("could not read file"),
+			ExpectedCode: exitFailure,
+		},
+		{
+			Err:          errorOf(dataurl.Parse("http://example.com/")),
+			ExpectedCode: exitNotADataURL,
+		},
+		{
+			Err:          errorOf(dataurl.Parse("data:text/plain")),
+			ExpectedCode: exitSyntaxError,
+		},
+		{
+			Err:          errorOf(dataurl.Parse("data:bad media type,x")),
+			ExpectedCode: exitBadMediaType,
+		},
+		{
+			Err:          errorOf(dataurl.Digest(dataurl.MustParse("data:,"), crypto.Hash(0))),
+			ExpectedCode: exitBadRequest,
+		},
+		{
+			// A more specific kind of dataurl.BadRequestComplainer, that does not have its own exit code.
+			Err:          dataurl.VerifyIntegrity(dataurl.MustParse("data:,"), "sha256-AAAA"),
+			ExpectedCode: exitBadRequest,
+		},
+		{
+			Err:          internalError{},
+			ExpectedCode: exitInternalError,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		if expected, actual := test.ExpectedCode, exitCode(test.Err); expected != actual {
+			t.Errorf("For test #%d, expected exit code %d, but actually got %d (for error: %v).", testNumber, expected, actual, test.Err)
+			continue
+		}
+	}
+}
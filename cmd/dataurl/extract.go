@@ -0,0 +1,103 @@
+package main
+
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/reiver/go-dataurl/internal/embedded"
+)
+
+
+func extractCommand(args []string) int {
+	const usage = "usage: dataurl extract [-dir directory] [-n] file..."
+
+	flags := flag.NewFlagSet("extract", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	var dir    string
+	var dryRun bool
+
+	flags.StringVar(&dir, "dir", "", "directory to write the extracted files to (the directory of each file, if not given)")
+	flags.BoolVar(&dryRun, "n", false, "dry run; only report what would be done")
+
+	if err := flags.Parse(args); nil != err || flags.NArg() < 1 {
+		fmt.Fprintln(stderr, usage)
+		return exitUsage
+	}
+
+	code := exitOK
+	for _, name := range flags.Args() {
+		if err := extractFile(name, dir, dryRun); nil != err {
+			code = fail("extract", err)
+		}
+	}
+
+	return code
+}
+
+
+// extractFile writes each data URL embedded in the file named 'name' out into
+// its own file (in directory 'dir'), and rewrites the file named 'name' so that
+// it refers to those files instead.
+//
+// What kind of file it is (and so where data URLs are looked for in it) is
+// decided by its file extension; see embedded.SyntaxOf(). Only data URLs that
+// end where their attribute, url(), link or string ends are extracted.
+func extractFile(name string, dir string, dryRun bool) error {
+	content, err := os.ReadFile(name)
+	if nil != err {
+		return err
+	}
+
+	base := filepath.Dir(name)
+	if "" == dir {
+		dir = base
+	}
+
+	var replacements []embedded.Span
+	for _, match := range findDataURLs(content, embedded.SyntaxOf(name)) {
+		parcel, err := embedded.Parse(match.URL)
+		if nil != err {
+			continue
+		}
+
+		digest := sha256.Sum256(parcel.Bytes())
+		filename := filepath.Join(dir, hex.EncodeToString(digest[:8]) + embedded.ExtensionFor(parcel.MediaType()))
+
+		reference, err := filepath.Rel(base, filename)
+		if nil != err {
+			reference = filename
+		}
+		reference = filepath.ToSlash(reference)
+
+		fmt.Fprintf(stdout, "%s:%d: %s (%d bytes) -> %s\n", name, match.Start, parcel.MediaType(), len(parcel.Bytes()), reference)
+
+		if !dryRun {
+			if err := os.MkdirAll(dir, 0755); nil != err {
+				return err
+			}
+			if err := os.WriteFile(filename, parcel.Bytes(), 0644); nil != err {
+				return err
+			}
+		}
+
+		replacements = append(replacements, embedded.Span{
+			Start: match.Start,
+			End:   match.End,
+			Text:  match.Escape(reference),
+		})
+	}
+
+	if dryRun || len(replacements) < 1 {
+		return nil
+	}
+
+	return os.WriteFile(name, embedded.Replace(content, replacements), 0644)
+}
+
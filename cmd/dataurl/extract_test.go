@@ -0,0 +1,99 @@
+package main
+
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/reiver/go-dataurl/internal/embedded"
+)
+
+
+func TestExtractFile(t *testing.T) {
+
+	const svg = `<svg xmlns='http://www.w3.org/2000/svg'></svg>`
+
+	tests := []struct{
+		Name     string
+		Content  string
+		Expected []string
+	}{
+		{
+			Name:     "style.css",
+			Content:  `.a{background:url("data:image/svg+xml;charset=utf8,%3Csvg xmlns='http://www.w3.org/2000/svg'%3E%3C/svg%3E")}`,
+			Expected: []string{svg},
+		},
+		{
+			Name:     "index.html",
+			Content:  `<img src="data:image/svg+xml,%3Csvg xmlns=&#39;http://www.w3.org/2000/svg&#39;%3E%3C/svg%3E"><p style="background:url('data:image/svg+xml,%3Csvg xmlns=&quot;http://www.w3.org/2000/svg&quot;/%3E')">`,
+			Expected: []string{svg, `<svg xmlns="http://www.w3.org/2000/svg"/>`},
+		},
+		{
+			Name:     "README.md",
+			Content:  "![svg](<data:image/svg+xml,%3Csvg xmlns='http://www.w3.org/2000/svg'%3E%3C/svg%3E>)\n",
+			Expected: []string{svg},
+		},
+		{
+			Name:     "data.json",
+			Content:  `{"icon":"data:image/svg+xml,%3Csvg xmlns=\"http://www.w3.org/2000/svg\"%3E%3C/svg%3E"}`,
+			Expected: []string{`<svg xmlns="http://www.w3.org/2000/svg"></svg>`},
+		},
+		{
+			// Cut off at the space; which is not where the url() ends. So it is not extracted.
+			Name:     "broken.css",
+			Content:  `.a{background:url(data:image/svg+xml,%3Csvg xmlns='http://www.w3.org/2000/svg'%3E%3C/svg%3E)}`,
+			Expected: nil,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		dir := t.TempDir()
+		name := filepath.Join(dir, test.Name)
+
+		if err := os.WriteFile(name, []byte(test.Content), 0644); nil != err {
+			t.Fatalf("For test #%d, could not write file: %s", testNumber, err)
+		}
+
+		if err := extractFile(name, "", false); nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		rewritten, err := os.ReadFile(name)
+		if nil != err {
+			t.Fatalf("For test #%d, could not read file: %s", testNumber, err)
+		}
+
+		if nil == test.Expected && test.Content != string(rewritten) {
+			t.Errorf("For test #%d, expected the file to be left as it was, but actually got %q.", testNumber, rewritten)
+			continue
+		}
+
+		var matches []embedded.Match
+		for _, match := range embedded.Find(rewritten, embedded.SyntaxOf(name)) {
+			if strings.HasSuffix(match.URL, ".svg") {
+				matches = append(matches, match)
+			}
+		}
+		if expected, actual := len(test.Expected), len(matches); expected != actual {
+			t.Errorf("For test #%d, expected %d references, but actually got %d: %q", testNumber, expected, actual, rewritten)
+			continue
+		}
+
+		for i, match := range matches {
+			extracted, err := os.ReadFile(filepath.Join(dir, match.URL))
+			if nil != err {
+				t.Errorf("For test #%d and reference #%d, could not read the extracted file: %s", testNumber, i, err)
+				continue
+			}
+
+			if expected, actual := test.Expected[i], string(extracted); expected != actual {
+				t.Errorf("For test #%d and reference #%d, expected %q, but actually got %q.", testNumber, i, expected, actual)
+				continue
+			}
+		}
+	}
+}
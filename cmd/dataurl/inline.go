@@ -0,0 +1,139 @@
+package main
+
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/reiver/go-dataurl"
+	"github.com/reiver/go-dataurl/internal/embedded"
+)
+
+
+func inlineCommand(args []string) int {
+	const usage = "usage: dataurl inline [-max bytes] [-allow-outside] [-n] file..."
+
+	flags := flag.NewFlagSet("inline", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	var max          int64
+	var allowOutside bool
+	var dryRun       bool
+
+	flags.Int64Var(&max, "max", 8192, "only inline files that are at most this many bytes")
+	flags.BoolVar(&allowOutside, "allow-outside", false, "also inline files that are outside of the directory of the file referring to them")
+	flags.BoolVar(&dryRun, "n", false, "dry run; only report what would be done")
+
+	if err := flags.Parse(args); nil != err || flags.NArg() < 1 {
+		fmt.Fprintln(stderr, usage)
+		return exitUsage
+	}
+
+	code := exitOK
+	for _, name := range flags.Args() {
+		if err := inlineFile(name, max, allowOutside, dryRun); nil != err {
+			code = fail("inline", err)
+		}
+	}
+
+	return code
+}
+
+
+// inlineFile replaces each reference to a local file (that is at most 'max' bytes)
+// in the file named 'name' with a data URL of the contents of that local file.
+//
+// Unless 'allowOutside' is true, only files inside of the directory that the file
+// named 'name' is in (or its sub-directories) are inlined. So that a reference such
+// as "../secret.txt" cannot pull in a file from elsewhere.
+func inlineFile(name string, max int64, allowOutside bool, dryRun bool) error {
+	content, err := os.ReadFile(name)
+	if nil != err {
+		return err
+	}
+
+	base := filepath.Dir(name)
+
+	var replacements []embedded.Span
+	for _, match := range findReferences(content, embedded.SyntaxOf(name)) {
+		reference, err := url.PathUnescape(match.URL)
+		if nil != err {
+			reference = match.URL
+		}
+
+		filename := filepath.Join(base, filepath.FromSlash(reference))
+
+		info, err := os.Stat(filename)
+		if nil != err || !info.Mode().IsRegular() {
+			fmt.Fprintf(stdout, "%s:%d: %s: skipped (not a local file)\n", name, match.Start, match.URL)
+			continue
+		}
+		if !allowOutside && !isInside(base, filename) {
+			fmt.Fprintf(stdout, "%s:%d: %s: skipped (outside of %s)\n", name, match.Start, match.URL, base)
+			continue
+		}
+		if max < info.Size() {
+			fmt.Fprintf(stdout, "%s:%d: %s: skipped (%d bytes is over %d bytes)\n", name, match.Start, match.URL, info.Size(), max)
+			continue
+		}
+
+		data, err := os.ReadFile(filename)
+		if nil != err {
+			return err
+		}
+
+		dataURL, err := dataurl.Encode(dataurl.GuessMediaType(filename, data), data, dataurl.EncodingBase64)
+		if nil != err {
+			return err
+		}
+
+		fmt.Fprintf(stdout, "%s:%d: %s: inlined (%d bytes -> %d byte data URL)\n", name, match.Start, match.URL, len(data), len(dataURL))
+
+		replacements = append(replacements, embedded.Span{
+			Start: match.Start,
+			End:   match.End,
+			Text:  match.Escape(dataURL),
+		})
+	}
+
+	if dryRun || len(replacements) < 1 {
+		return nil
+	}
+
+	return os.WriteFile(name, embedded.Replace(content, replacements), 0644)
+}
+
+
+// isInside returns whether the file named 'filename' is inside of the directory
+// 'dir' (or one of its sub-directories); after following symbolic links.
+func isInside(dir string, filename string) bool {
+	dir, err := filepath.EvalSymlinks(dir)
+	if nil != err {
+		return false
+	}
+	dir, err = filepath.Abs(dir)
+	if nil != err {
+		return false
+	}
+
+	filename, err = filepath.EvalSymlinks(filename)
+	if nil != err {
+		return false
+	}
+	filename, err = filepath.Abs(filename)
+	if nil != err {
+		return false
+	}
+
+	relative, err := filepath.Rel(dir, filename)
+	if nil != err {
+		return false
+	}
+
+	return ".." != relative && !strings.HasPrefix(relative, ".." + string(filepath.Separator))
+}
@@ -0,0 +1,98 @@
+package main
+
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reiver/go-dataurl/internal/embedded"
+)
+
+
+func TestInlineFile(t *testing.T) {
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "site")
+
+	if err := os.MkdirAll(filepath.Join(dir, "images"), 0755); nil != err {
+		t.Fatalf("Could not make directory: %s", err)
+	}
+	files := map[string]string{
+		filepath.Join(root, "secret.txt"):          "secret",
+		filepath.Join(dir, "images", "a b.svg"):    `<svg xmlns='http://www.w3.org/2000/svg'></svg>`,
+		filepath.Join(dir, "note.txt"):             "Hello world!",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0644); nil != err {
+			t.Fatalf("Could not write file: %s", err)
+		}
+	}
+
+	tests := []struct{
+		Name         string
+		Content      string
+		AllowOutside bool
+		Expected     []string
+	}{
+		{
+			Name:     "index.html",
+			Content:  `<img src="images/a%20b.svg"><a href="../secret.txt">x</a><a href=note.txt>y</a>`,
+			Expected: []string{"data:image/svg+xml;base64,PHN2ZyB4bWxucz0naHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmcnPjwvc3ZnPg==", "../secret.txt", "data:text/plain;charset=utf-8;base64,SGVsbG8gd29ybGQh"},
+		},
+		{
+			Name:         "outside.html",
+			Content:      `<a href="../secret.txt">x</a>`,
+			AllowOutside: true,
+			Expected:     []string{"data:text/plain;charset=utf-8;base64,c2VjcmV0"},
+		},
+		{
+			Name:     "style.css",
+			Content:  `.a{background:url( "images/a b.svg" )} .b{background:url(../secret.txt)}`,
+			Expected: []string{"data:image/svg+xml;base64,PHN2ZyB4bWxucz0naHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmcnPjwvc3ZnPg==", "../secret.txt"},
+		},
+		{
+			Name:     "README.md",
+			Content:  "[note](note.txt \"A note\") and [secret](../secret.txt)\n",
+			Expected: []string{"data:text/plain;charset=utf-8;base64,SGVsbG8gd29ybGQh", "../secret.txt"},
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		name := filepath.Join(dir, test.Name)
+
+		if err := os.WriteFile(name, []byte(test.Content), 0644); nil != err {
+			t.Fatalf("For test #%d, could not write file: %s", testNumber, err)
+		}
+
+		if err := inlineFile(name, 8192, test.AllowOutside, false); nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: (%T) %q", testNumber, err, err)
+			continue
+		}
+
+		rewritten, err := os.ReadFile(name)
+		if nil != err {
+			t.Fatalf("For test #%d, could not read file: %s", testNumber, err)
+		}
+
+		var actual []string
+		for _, match := range embedded.Find(rewritten, embedded.SyntaxOf(name)) {
+			if "" != match.URL {
+				actual = append(actual, match.URL)
+			}
+		}
+
+		if expected, actual := len(test.Expected), len(actual); expected != actual {
+			t.Errorf("For test #%d, expected %d URLs, but actually got %d: %q", testNumber, expected, actual, rewritten)
+			continue
+		}
+
+		for i := range actual {
+			if expected, actual := test.Expected[i], actual[i]; expected != actual {
+				t.Errorf("For test #%d and URL #%d, expected %q, but actually got %q.", testNumber, i, expected, actual)
+				continue
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+
+// readInput returns the contents of the file named 'name', or (if 'name' is
+// the empty string or "-") the contents of STDIN.
+func readInput(name string) ([]byte, error) {
+	if "" == name || "-" == name {
+		return io.ReadAll(stdin)
+	}
+
+	return os.ReadFile(name)
+}
+
+
+// readDataURL returns the data URL given on the command-line, or (if there
+// wasn't one) the data URL read from STDIN.
+//
+// Surrounding whitespace (such as a trailing newline) is removed.
+func readDataURL(args []string) (string, error) {
+	if 0 < len(args) {
+		return strings.TrimSpace(args[0]), nil
+	}
+
+	p, err := io.ReadAll(stdin)
+	if nil != err {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(p)), nil
+}
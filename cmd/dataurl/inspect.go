@@ -0,0 +1,79 @@
+package main
+
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+func inspectCommand(args []string) int {
+	flags := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	if err := flags.Parse(args); nil != err || 1 < flags.NArg() {
+		fmt.Fprintln(stderr, "usage: dataurl inspect [data-url]")
+		return exitUsage
+	}
+
+	dataURL, err := readDataURL(flags.Args())
+	if nil != err {
+		return fail("inspect", err)
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 8, 1, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "encoded size:\t%d bytes\n", len(dataURL))
+
+	parcel, err := dataurl.Parse(dataURL)
+	if nil != err {
+		fmt.Fprintf(w, "error:\t%s\n", err)
+		if offset, ok := dataurl.SyntaxErrorOffset(err); ok {
+			fmt.Fprintf(w, "error offset:\t%d\n", offset)
+		}
+		return exitCode(err)
+	}
+
+	fmt.Fprintf(w, "media type:\t%s\n", parcel.MediaType())
+
+	if mimeType, params, err := mime.ParseMediaType(parcel.MediaType()); nil == err {
+		fmt.Fprintf(w, "type:\t%s\n", mimeType)
+
+		var names []string
+		for name := range params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Fprintf(w, "parameter:\t%s=%s\n", name, params[name])
+		}
+	}
+
+	fmt.Fprintf(w, "encoding:\t%s\n", encodingOf(dataURL))
+	fmt.Fprintf(w, "decoded size:\t%d bytes\n", len(parcel.Bytes()))
+	fmt.Fprintf(w, "sniffed type:\t%s\n", http.DetectContentType(parcel.Bytes()))
+
+	return exitOK
+}
+
+
+// encodingOf returns how the (already successfully parsed) data URL 'dataURL'
+// is encoded. This mirrors how dataurl.Parse() decides.
+func encodingOf(dataURL string) dataurl.Encoding {
+	mediaType, _, _ := strings.Cut(dataURL, ",")
+	if strings.HasSuffix(mediaType, ";base64") {
+		return dataurl.EncodingBase64
+	}
+
+	return dataurl.EncodingPercent
+}
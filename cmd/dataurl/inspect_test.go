@@ -0,0 +1,93 @@
+package main
+
+
+import (
+	"strings"
+	"testing"
+)
+
+
+func TestInspectCommand(t *testing.T) {
+
+	tests := []struct{
+		Args          []string
+		ExpectedCode  int
+		ExpectedLines []string
+	}{
+		{
+			Args:         []string{"inspect", "data:text/plain;charset=utf-8;base64,SGVsbG8="},
+			ExpectedCode: exitOK,
+			ExpectedLines: []string{
+				"encoded size: 45 bytes",
+				"media type:   text/plain;charset=utf-8",
+				"type:         text/plain",
+				"parameter:    charset=utf-8",
+				"encoding:     base64",
+				"decoded size: 5 bytes",
+				"sniffed type: text/plain; charset=utf-8",
+			},
+		},
+		{
+			Args:         []string{"inspect", "data:,Hello%20world!"},
+			ExpectedCode: exitOK,
+			ExpectedLines: []string{
+				"encoded size: 20 bytes",
+				"media type:   text/plain;charset=US-ASCII",
+				"type:         text/plain",
+				"parameter:    charset=US-ASCII",
+				"encoding:     percent",
+				"decoded size: 12 bytes",
+				"sniffed type: text/plain; charset=utf-8",
+			},
+		},
+		{
+			Args:         []string{"inspect", "http://example.com/"},
+			ExpectedCode: exitNotADataURL,
+			ExpectedLines: []string{
+				"encoded size: 19 bytes",
+				"error:        Bad Request: not a data URL.",
+			},
+		},
+		{
+			Args:         []string{"inspect", "data:text/plain"},
+			ExpectedCode: exitSyntaxError,
+			ExpectedLines: []string{
+				"encoded size: 15 bytes",
+				"error:        Bad Request: Syntax Error: Data URL does not contain a comma.",
+				"error offset: 15",
+			},
+		},
+		{
+			Args:         []string{"inspect", "data:bad media type,x"},
+			ExpectedCode: exitBadMediaType,
+			ExpectedLines: []string{
+				"encoded size: 21 bytes",
+				"error:        Bad Request: Bad Media Type: mime: expected slash after first token",
+			},
+		},
+		{
+			Args:          []string{"inspect", "data:,a", "data:,b"},
+			ExpectedCode:  exitUsage,
+			ExpectedLines: nil,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		code, stdout, _ := runWith("", test.Args...)
+
+		if expected, actual := test.ExpectedCode, code; expected != actual {
+			t.Errorf("For test #%d, expected exit code %d, but actually got %d.", testNumber, expected, actual)
+			continue
+		}
+
+		var expected string
+		if nil != test.ExpectedLines {
+			expected = strings.Join(test.ExpectedLines, "\n") + "\n"
+		}
+		if actual := stdout; expected != actual {
+			t.Errorf("For test #%d, expected STDOUT:\n%s\nbut actually got:\n%s", testNumber, expected, actual)
+			continue
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+func lintCommand(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	var maxLength int
+
+	flags.IntVar(&maxLength, "max", dataurl.DefaultLintMaxLength, "longest (in bytes) a data URL may be (negative for no limit)")
+
+	if err := flags.Parse(args); nil != err || 1 < flags.NArg() {
+		fmt.Fprintln(stderr, "usage: dataurl lint [-max bytes] [data-url]")
+		return exitUsage
+	}
+
+	dataURL, err := readDataURL(flags.Args())
+	if nil != err {
+		return fail("lint", err)
+	}
+
+	// An invalid data URL exits the same way as with the other commands.
+	if _, err := dataurl.Parse(dataURL); nil != err {
+		return fail("lint", err)
+	}
+
+	findings := dataurl.LintConfig{MaxLength: maxLength}.Lint(dataURL)
+
+	code := exitOK
+	for _, finding := range findings {
+		fmt.Fprintln(stdout, finding)
+		if nil != finding.Fix {
+			fmt.Fprintf(stdout, "\tfix: replace bytes %d-%d with %q\n", finding.Fix.Start, finding.Fix.End, finding.Fix.Replacement)
+		}
+
+		if dataurl.SeverityWarning <= finding.Severity {
+			code = exitFindings
+		}
+	}
+
+	return code
+}
@@ -0,0 +1,68 @@
+package main
+
+
+import (
+	"testing"
+)
+
+
+func TestLintCommand(t *testing.T) {
+
+	tests := []struct{
+		Args           []string
+		ExpectedCode   int
+		ExpectedStdout string
+	}{
+		{
+			Args:           []string{"lint", "data:,Hello%20world!"},
+			ExpectedCode:   exitOK,
+			ExpectedStdout: "",
+		},
+		{
+			// Only warnings and errors change the exit code; not info.
+			Args:           []string{"lint", "data:text/plain;charset=US-ASCII,Hello"},
+			ExpectedCode:   exitOK,
+			ExpectedStdout: "16-32 info redundant-charset: \"charset=US-ASCII\" is implied by default\n" +
+			                "\tfix: replace bytes 15-32 with \"\"\n",
+		},
+		{
+			Args:           []string{"lint", "-max", "10", "data:,Hello%20world!"},
+			ExpectedCode:   exitFindings,
+			ExpectedStdout: "0-20 warning too-large: data URL is 20 bytes, which is more than 10 bytes\n",
+		},
+		{
+			Args:           []string{"lint", "http://example.com/"},
+			ExpectedCode:   exitNotADataURL,
+			ExpectedStdout: "",
+		},
+		{
+			Args:           []string{"lint", "data:text/plain"},
+			ExpectedCode:   exitSyntaxError,
+			ExpectedStdout: "",
+		},
+		{
+			Args:           []string{"lint", "data:bad media type,x"},
+			ExpectedCode:   exitBadMediaType,
+			ExpectedStdout: "",
+		},
+		{
+			Args:           []string{"lint", "-max", "ten", "data:,"},
+			ExpectedCode:   exitUsage,
+			ExpectedStdout: "",
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		code, stdout, _ := runWith("", test.Args...)
+
+		if expected, actual := test.ExpectedCode, code; expected != actual {
+			t.Errorf("For test #%d, expected exit code %d, but actually got %d.", testNumber, expected, actual)
+			continue
+		}
+		if expected, actual := test.ExpectedStdout, stdout; expected != actual {
+			t.Errorf("For test #%d, expected STDOUT %q, but actually got %q.", testNumber, expected, actual)
+			continue
+		}
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Command dataurl builds, takes apart and inspects data URLs (as defined by RFC 2397).
+
+Usage:
+
+	dataurl encode  [-type media-type] [-percent | -shortest] [file]
+	dataurl decode  [-o file] [data-url]
+	dataurl inspect [data-url]
+	dataurl lint    [-max bytes] [data-url]
+	dataurl extract [-dir directory] [-n] file...
+	dataurl inline  [-max bytes] [-allow-outside] [-n] file...
+
+If no file is given to "encode", then the contents are read from STDIN.
+
+If no data URL is given to "decode", "inspect" or "lint", then the data URL is read from STDIN.
+
+"extract" writes each data URL embedded in the given HTML, CSS, SVG, Markdown or
+JSON files out into its own file (named by the hash of its contents), and rewrites
+the given files to refer to those files instead.
+
+"inline" does the opposite. It replaces each reference to a local file (that is
+not larger than -max bytes) with a data URL. Only files in the directory of the
+given file (or its sub-directories) are inlined; unless -allow-outside is given.
+
+"lint" reports each problem found with the data URL (see dataurl.Lint()), with
+where it is (as a byte range) and (if there is one) a suggested fix. A data URL
+longer than -max bytes is reported as too large.
+
+With -n, "extract" and "inline" only report what they would do.
+
+Exit codes:
+
+	0  success
+	1  some other error (ex: could not read or write a file)
+	2  bad command-line usage
+	3  not a data URL                       (dataurl.NotADataUrlComplainer)
+	4  syntax error in data URL             (dataurl.SyntaxErrorComplainer)
+	5  bad media type                       (dataurl.BadMediaTypeComplainer)
+	6  some other bad request               (dataurl.BadRequestComplainer)
+	7  internal error                       (dataurl.InternalErrorComplainer)
+	8  "lint" found a warning or an error
+*/
+package main
+
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+
+// stdin, stdout and stderr are what the commands read from, and write to. (They are variables
+// so that the tests can replace them.)
+var (
+	stdin  io.Reader = os.Stdin
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+)
+
+
+const usage = `usage: dataurl <command> [arguments]
+
+commands:
+	encode   create a data URL from a file (or STDIN)
+	decode   write the contents of a data URL to a file (or STDOUT)
+	inspect  describe a data URL
+	lint     report problems with a data URL
+	extract  move data URLs embedded in files out into their own files
+	inline   replace references to small local files with data URLs
+`
+
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+
+func run(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	command, args := args[0], args[1:]
+
+	switch command {
+	case "encode":
+		return encodeCommand(args)
+	case "decode":
+		return decodeCommand(args)
+	case "inspect":
+		return inspectCommand(args)
+	case "lint":
+		return lintCommand(args)
+	case "extract":
+		return extractCommand(args)
+	case "inline":
+		return inlineCommand(args)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "dataurl: unknown command %q\n\n", command)
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+}
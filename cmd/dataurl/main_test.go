@@ -0,0 +1,57 @@
+package main
+
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+
+// runWith runs the dataurl command with the arguments 'args', and with 'input' as STDIN. It returns
+// the exit code, and what was written to STDOUT and to STDERR.
+func runWith(input string, args ...string) (int, string, string) {
+	var out, errOut bytes.Buffer
+
+	stdin, stdout, stderr = strings.NewReader(input), &out, &errOut
+	defer func() {
+		stdin, stdout, stderr = os.Stdin, os.Stdout, os.Stderr
+	}()
+
+	code := run(args)
+
+	return code, out.String(), errOut.String()
+}
+
+
+func TestRun(t *testing.T) {
+
+	tests := []struct{
+		Args         []string
+		ExpectedCode int
+	}{
+		{
+			Args:         nil,
+			ExpectedCode: exitUsage,
+		},
+		{
+			Args:         []string{"help"},
+			ExpectedCode: exitOK,
+		},
+		{
+			Args:         []string{"unknown"},
+			ExpectedCode: exitUsage,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		code, _, _ := runWith("", test.Args...)
+
+		if expected, actual := test.ExpectedCode, code; expected != actual {
+			t.Errorf("For test #%d, expected exit code %d, but actually got %d.", testNumber, expected, actual)
+			continue
+		}
+	}
+}
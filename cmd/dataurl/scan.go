@@ -0,0 +1,83 @@
+package main
+
+
+import (
+	"strings"
+
+	"github.com/reiver/go-dataurl/internal/embedded"
+)
+
+
+// findDataURLs returns each (possible) data URL embedded in 'content'; which
+// is an HTML, CSS, SVG, Markdown or JSON file, as specified by 'syntax'.
+//
+// Whatever this returns still needs to be checked with dataurl.Parse().
+func findDataURLs(content []byte, syntax embedded.Syntax) []embedded.Match {
+	var matches []embedded.Match
+
+	for _, match := range embedded.Find(content, syntax) {
+		if !embedded.HasScheme(match.URL, "data:") {
+			continue
+		}
+
+		matches = append(matches, match)
+	}
+
+	return matches
+}
+
+
+// findReferences returns each local file reference in 'content'; which is an
+// HTML, CSS, SVG or Markdown file, as specified by 'syntax'.
+//
+// References are looked for in src, href and xlink:href attributes, CSS url()s
+// and Markdown links.
+//
+// References that are data URLs, have a scheme (ex: "https:"), are protocol
+// relative (ex: "//example.com/"), or are just a fragment (ex: "#top") are
+// not local, and are not returned. Nor is anything in a JSON file; since any
+// string in it could look like a reference.
+func findReferences(content []byte, syntax embedded.Syntax) []embedded.Match {
+	if embedded.SyntaxJSON == syntax {
+		return nil
+	}
+
+	var matches []embedded.Match
+
+	for _, match := range embedded.Find(content, syntax) {
+		switch match.Attribute {
+		case "", "style", "src", "href", "xlink:href":
+		default:
+			continue
+		}
+
+		if !isLocalReference(match.URL) {
+			continue
+		}
+
+		matches = append(matches, match)
+	}
+
+	return matches
+}
+
+
+func isLocalReference(reference string) bool {
+	switch {
+	case "" == reference:
+		return false
+	case strings.HasPrefix(reference, "#"):
+		return false
+	case strings.HasPrefix(reference, "//"):
+		return false
+	case strings.HasPrefix(reference, "/"):
+		return false
+	}
+
+	// Does it have a scheme? (Ex: "data:", "http:", "mailto:")
+	if index := strings.IndexAny(reference, ":/?#"); -1 != index && ':' == reference[index] {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,124 @@
+package main
+
+
+import (
+	"testing"
+
+	"github.com/reiver/go-dataurl/internal/embedded"
+)
+
+
+func TestFindDataURLs(t *testing.T) {
+
+	tests := []struct{
+		Content  string
+		Syntax   embedded.Syntax
+		Expected []string
+	}{
+		{
+			Content:  `<img src="data:image/gif;base64,R0lGODlhAQABAAAAACw=" alt="">`,
+			Syntax:   embedded.SyntaxHTML,
+			Expected: []string{`data:image/gif;base64,R0lGODlhAQABAAAAACw=`},
+		},
+		{
+			Content:  `.a{background:url(data:image/png;base64,iVBORw0KGgo=)} .b{background:url('data:,Hi%21')}`,
+			Syntax:   embedded.SyntaxCSS,
+			Expected: []string{`data:image/png;base64,iVBORw0KGgo=`, `data:,Hi%21`},
+		},
+		{
+			Content:  "![dot](data:text/plain;charset=utf-8,Hello%20world)\n",
+			Syntax:   embedded.SyntaxMarkdown,
+			Expected: []string{`data:text/plain;charset=utf-8,Hello%20world`},
+		},
+		{
+			Content:  `{"avatar":"data:image/png;base64,AAAA","name":"Joe"}`,
+			Syntax:   embedded.SyntaxJSON,
+			Expected: []string{`data:image/png;base64,AAAA`},
+		},
+		{
+			Content:  `.a{background:url("data:image/svg+xml;charset=utf8,%3Csvg xmlns='http://www.w3.org/2000/svg'%3E%3C/svg%3E")}`,
+			Syntax:   embedded.SyntaxCSS,
+			Expected: []string{`data:image/svg+xml;charset=utf8,%3Csvg xmlns='http://www.w3.org/2000/svg'%3E%3C/svg%3E`},
+		},
+		{
+			Content:  `.a{background:url(data:image/svg+xml,%3Csvg xmlns='http://www.w3.org/2000/svg'%3E%3C/svg%3E)}`,
+			Syntax:   embedded.SyntaxCSS,
+			Expected: nil,
+		},
+		{
+			Content:  `<a href="https://example.com/">no data URLs here</a>`,
+			Syntax:   embedded.SyntaxHTML,
+			Expected: nil,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		actual := findDataURLs([]byte(test.Content), test.Syntax)
+
+		if expected, actual := len(test.Expected), len(actual); expected != actual {
+			t.Errorf("For test #%d, expected %d data URLs, but actually got %d.", testNumber, expected, actual)
+			continue
+		}
+
+		for i, match := range actual {
+			if expected, actual := test.Expected[i], match.URL; expected != actual {
+				t.Errorf("For test #%d and data URL #%d, expected %q, but actually got %q.", testNumber, i, expected, actual)
+				continue
+			}
+			if expected, actual := match.URL, test.Content[match.Start:match.End]; expected != actual {
+				t.Errorf("For test #%d and data URL #%d, expected span to contain %q, but actually contained %q.", testNumber, i, expected, actual)
+				continue
+			}
+		}
+	}
+}
+
+
+func TestFindReferences(t *testing.T) {
+
+	tests := []struct{
+		Content  string
+		Syntax   embedded.Syntax
+		Expected []string
+	}{
+		{
+			Content:  `<img src="images/dot.png"><a href="https://example.com/">x</a><a href="#top">top</a>`,
+			Syntax:   embedded.SyntaxHTML,
+			Expected: []string{`images/dot.png`},
+		},
+		{
+			Content:  `.a{background:url(a.png)} .b{background:url("b.png")} .c{background:url(data:,x)}`,
+			Syntax:   embedded.SyntaxCSS,
+			Expected: []string{`a.png`, `b.png`},
+		},
+		{
+			Content:  `![dot](dot.gif "A dot") and [site](//example.com/)`,
+			Syntax:   embedded.SyntaxMarkdown,
+			Expected: []string{`dot.gif`},
+		},
+		{
+			Content:  `<use xlink:href='sprite.svg'/><script src="/app.js"></script>`,
+			Syntax:   embedded.SyntaxHTML,
+			Expected: []string{`sprite.svg`},
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		actual := findReferences([]byte(test.Content), test.Syntax)
+
+		if expected, actual := len(test.Expected), len(actual); expected != actual {
+			t.Errorf("For test #%d, expected %d references, but actually got %d: %v", testNumber, expected, actual, findReferences([]byte(test.Content), test.Syntax))
+			continue
+		}
+
+		for i, match := range actual {
+			if expected, actual := test.Expected[i], match.URL; expected != actual {
+				t.Errorf("For test #%d and reference #%d, expected %q, but actually got %q.", testNumber, i, expected, actual)
+				continue
+			}
+		}
+	}
+}
+
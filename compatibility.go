@@ -0,0 +1,466 @@
+package dataurl
+
+
+import (
+	"fmt"
+	"path"
+)
+
+
+// Profile is used to specify what is going to consume a data URL. (Ex: a modern browser, or an
+// email client.)
+//
+// Different consumers accept data URLs in different places, of different lengths, and of different
+// media types. dataurl.Compatibility() uses a Profile to report what will and will not work.
+type Profile int
+
+
+const (
+	// ProfileModernBrowsers is current versions of Chrome, Edge, Firefox and Safari.
+	ProfileModernBrowsers Profile = iota
+
+	// ProfileEmailClients is HTML email, as shown by common email clients (ex: Gmail, Outlook,
+	// Apple Mail).
+	ProfileEmailClients
+
+	// ProfileLegacyIE is Internet Explorer 8 to 11.
+	ProfileLegacyIE
+
+	// ProfileElectron is an Electron application, loading its own content.
+	ProfileElectron
+)
+
+
+// known returns whether 'profile' is one of the Profile constants.
+func (profile Profile) known() bool {
+	switch profile {
+	case ProfileModernBrowsers, ProfileEmailClients, ProfileLegacyIE, ProfileElectron:
+		return true
+	default:
+		return false
+	}
+}
+
+
+// String returns the name of the profile.
+func (profile Profile) String() string {
+	switch profile {
+	case ProfileModernBrowsers:
+		return "modern-browsers"
+	case ProfileEmailClients:
+		return "email-clients"
+	case ProfileLegacyIE:
+		return "legacy-ie"
+	case ProfileElectron:
+		return "electron"
+	default:
+		return "unknown"
+	}
+}
+
+
+// Usage is used to specify where a data URL is put. (Ex: the "src" of an <img>.)
+type Usage int
+
+
+const (
+	// UsageImage is an image. For example: <img src="...">
+	UsageImage Usage = iota
+
+	// UsageCSS is a CSS url(). For example: background-image: url(...)
+	UsageCSS
+
+	// UsageNavigation is top-level navigation. For example: <a href="..."> or window.location = "..."
+	UsageNavigation
+
+	// UsageIframe is the document of an <iframe>. For example: <iframe src="...">
+	UsageIframe
+
+	// UsageScript is a script. For example: <script src="...">
+	UsageScript
+
+	// UsageSVGUse is the reference of an SVG <use>. For example: <use href="...#icon">
+	UsageSVGUse
+
+	// UsageDownload is a download link. For example: <a download="name" href="...">
+	UsageDownload
+)
+
+
+// allUsages are all the usages; in the order they appear in a CompatibilityReport.
+var allUsages = []Usage{
+	UsageImage,
+	UsageCSS,
+	UsageNavigation,
+	UsageIframe,
+	UsageScript,
+	UsageSVGUse,
+	UsageDownload,
+}
+
+
+// Usages returns all the usages; in the order they appear in a CompatibilityReport.
+//
+// The returned slice is a copy; so modifying it does not affect dataurl.Compatibility().
+func Usages() []Usage {
+	return append([]Usage(nil), allUsages...)
+}
+
+
+// String returns the name of the usage.
+func (usage Usage) String() string {
+	switch usage {
+	case UsageImage:
+		return "image"
+	case UsageCSS:
+		return "css"
+	case UsageNavigation:
+		return "navigation"
+	case UsageIframe:
+		return "iframe"
+	case UsageScript:
+		return "script"
+	case UsageSVGUse:
+		return "svg-use"
+	case UsageDownload:
+		return "download"
+	default:
+		return "unknown"
+	}
+}
+
+
+// Support is whether a data URL works for a Usage.
+type Support int
+
+
+const (
+	// SupportNo means that it does not work.
+	SupportNo Support = iota
+
+	// SupportConditional means that it works, but only if the conditions (in the CompatibilityResult) are met.
+	SupportConditional
+
+	// SupportYes means that it works.
+	SupportYes
+)
+
+
+// String returns the name of the support.
+func (support Support) String() string {
+	switch support {
+	case SupportNo:
+		return "no"
+	case SupportConditional:
+		return "conditional"
+	case SupportYes:
+		return "yes"
+	default:
+		return "unknown"
+	}
+}
+
+
+// CompatibilityResult is whether a data URL works for a single Usage.
+type CompatibilityResult struct {
+	Usage   Usage
+	Support Support
+
+	// Reason explains why (for SupportNo and SupportConditional) or is empty (for SupportYes).
+	Reason string
+}
+
+
+// String returns the result, as a human would write it.
+//
+// For example:
+//
+//	svg-use: no (external references to data URLs in SVG <use> are blocked)
+func (result CompatibilityResult) String() string {
+	if "" == result.Reason {
+		return fmt.Sprintf("%s: %s", result.Usage, result.Support)
+	}
+
+	return fmt.Sprintf("%s: %s (%s)", result.Usage, result.Support, result.Reason)
+}
+
+
+// CompatibilityReport is what dataurl.Compatibility() returns.
+type CompatibilityReport struct {
+	Profile Profile
+
+	// Length is the length (in bytes) of the shortest data URL for the Parcel. Length limits are
+	// checked against this.
+	Length int
+
+	// Results has a CompatibilityResult for each of dataurl.Usages(), in the same order.
+	Results []CompatibilityResult
+}
+
+
+// Support returns whether the data URL works for 'usage'.
+func (report CompatibilityReport) Support(usage Usage) Support {
+	for _, result := range report.Results {
+		if usage == result.Usage {
+			return result.Support
+		}
+	}
+
+	return SupportNo
+}
+
+
+// compatibilityRule is a row in the compatibilityRules table.
+type compatibilityRule struct {
+	Profiles   []Profile // The profiles this rule applies to. nil means every profile.
+	Usages     []Usage   // The usages this rule applies to. nil means every usage.
+	MediaTypes []string  // Patterns (see path.Match) of the media types this rule applies to. nil means every media type.
+	MinLength  int       // This rule only applies to data URLs longer than this. Zero means every length.
+
+	Support Support
+	Reason  string
+}
+
+
+func (rule compatibilityRule) matches(profile Profile, usage Usage, mimeType string, length int) bool {
+	if nil != rule.Profiles && !containsProfile(rule.Profiles, profile) {
+		return false
+	}
+	if nil != rule.Usages && !containsUsage(rule.Usages, usage) {
+		return false
+	}
+	if nil != rule.MediaTypes && !matchesMediaType(rule.MediaTypes, mimeType) {
+		return false
+	}
+	if length <= rule.MinLength {
+		return false
+	}
+
+	return true
+}
+
+
+func containsProfile(profiles []Profile, profile Profile) bool {
+	for _, p := range profiles {
+		if profile == p {
+			return true
+		}
+	}
+	return false
+}
+
+
+func containsUsage(usages []Usage, usage Usage) bool {
+	for _, u := range usages {
+		if usage == u {
+			return true
+		}
+	}
+	return false
+}
+
+
+func matchesMediaType(patterns []string, mimeType string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, mimeType); nil == err && matched {
+			return true
+		}
+	}
+	return false
+}
+
+
+const (
+	legacyIEMaxLength = 32 * 1024
+
+	reasonCSPImage       = "the Content-Security-Policy (if any) must allow data: in img-src"
+	reasonCSPStyle       = "the Content-Security-Policy (if any) must allow data: in img-src (or font-src, for fonts)"
+	reasonCSPScript      = "the Content-Security-Policy (if any) must allow data: in script-src"
+	reasonCSPFrame       = "the Content-Security-Policy (if any) must allow data: in frame-src; and the document runs in an opaque origin"
+	reasonNavigation     = "top-level navigation to data URLs is blocked (except for downloads)"
+	reasonSVGUse         = "external references to data URLs in SVG <use> are blocked"
+	reasonEmail          = "most email clients (ex: Gmail, Outlook) strip or block data URLs; use cid: attachments instead"
+	reasonEmailActive    = "email clients do not run scripts or show iframes"
+	reasonLegacyIEUsage  = "Internet Explorer only supports data URLs for images and CSS"
+	reasonLegacyIELong   = "Internet Explorer 8 does not support data URLs longer than 32 KB"
+	reasonNotAnImage     = "the media type is not an image"
+	reasonNotAScript     = "browsers refuse to run scripts with this media type"
+	reasonNilParcel      = "there is no parcel (it is nil)"
+	reasonUnknownProfile = "the profile is unknown"
+)
+
+
+// compatibilityRules is the table that dataurl.Compatibility() uses. For each profile and usage,
+// the first rule that matches is used. If none match, then the usage is SupportYes.
+//
+// Keep the more specific rules BEFORE the more general rules.
+var compatibilityRules = []compatibilityRule{
+	// Things that do not work for any profile.
+	{
+		Usages:     []Usage{UsageImage},
+		MediaTypes: []string{"text/*", "application/json", "font/*", "audio/*", "video/*"},
+		Support:    SupportNo,
+		Reason:     reasonNotAnImage,
+	},
+	{
+		Usages:     []Usage{UsageScript},
+		MediaTypes: []string{"image/*", "audio/*", "video/*", "text/csv"},
+		Support:    SupportNo,
+		Reason:     reasonNotAScript,
+	},
+	{
+		Usages:  []Usage{UsageSVGUse},
+		Support: SupportNo,
+		Reason:  reasonSVGUse,
+	},
+
+	// Email clients.
+	{
+		Profiles: []Profile{ProfileEmailClients},
+		Usages:   []Usage{UsageScript, UsageIframe},
+		Support:  SupportNo,
+		Reason:   reasonEmailActive,
+	},
+	{
+		Profiles: []Profile{ProfileEmailClients},
+		Support:  SupportNo,
+		Reason:   reasonEmail,
+	},
+
+	// Internet Explorer.
+	{
+		Profiles: []Profile{ProfileLegacyIE},
+		Usages:   []Usage{UsageNavigation, UsageIframe, UsageScript, UsageDownload},
+		Support:  SupportNo,
+		Reason:   reasonLegacyIEUsage,
+	},
+	{
+		Profiles:  []Profile{ProfileLegacyIE},
+		MinLength: legacyIEMaxLength,
+		Support:   SupportConditional,
+		Reason:    reasonLegacyIELong,
+	},
+
+	// Chromium (and so Electron), Firefox and Safari.
+	{
+		Profiles: []Profile{ProfileModernBrowsers},
+		Usages:   []Usage{UsageNavigation},
+		Support:  SupportNo,
+		Reason:   reasonNavigation,
+	},
+	{
+		Profiles: []Profile{ProfileElectron},
+		Usages:   []Usage{UsageNavigation},
+		Support:  SupportConditional,
+		Reason:   "only when loaded by the main process (ex: BrowserWindow.loadURL()); navigation started by a page is blocked",
+	},
+	{
+		Profiles: []Profile{ProfileModernBrowsers, ProfileElectron},
+		Usages:   []Usage{UsageImage},
+		Support:  SupportConditional,
+		Reason:   reasonCSPImage,
+	},
+	{
+		Profiles: []Profile{ProfileModernBrowsers, ProfileElectron},
+		Usages:   []Usage{UsageCSS},
+		Support:  SupportConditional,
+		Reason:   reasonCSPStyle,
+	},
+	{
+		Profiles: []Profile{ProfileModernBrowsers, ProfileElectron},
+		Usages:   []Usage{UsageScript},
+		Support:  SupportConditional,
+		Reason:   reasonCSPScript,
+	},
+	{
+		Profiles: []Profile{ProfileModernBrowsers, ProfileElectron},
+		Usages:   []Usage{UsageIframe},
+		Support:  SupportConditional,
+		Reason:   reasonCSPFrame,
+	},
+}
+
+
+// Compatibility reports where (for each of dataurl.Usages()) a data URL with the contents and media type
+// of 'parcel' will and will not work, for the consumer specified by 'profile'.
+//
+// What is reported comes from a table of rules in this package. It describes what is commonly true of
+// each profile; a specific version of a browser (or email client) might differ.
+//
+// If 'parcel' is nil, or 'profile' is not one of the Profile constants, then every usage is reported
+// as SupportNo.
+//
+// Example usage:
+//
+//	report := dataurl.Compatibility(parcel, dataurl.ProfileLegacyIE)
+//
+//	if dataurl.SupportYes != report.Support(dataurl.UsageCSS) {
+//		//@TODO
+//	}
+//
+//	for _, result := range report.Results {
+//		fmt.Println(result)
+//	}
+func Compatibility(parcel Parcel, profile Profile) CompatibilityReport {
+	if nil == parcel || !profile.known() {
+		reason := reasonNilParcel
+		if nil != parcel {
+			reason = reasonUnknownProfile
+		}
+
+		report := CompatibilityReport{
+			Profile: profile,
+		}
+
+		for _, usage := range allUsages {
+			report.Results = append(report.Results, CompatibilityResult{
+				Usage:   usage,
+				Support: SupportNo,
+				Reason:  reason,
+			})
+		}
+
+		return report
+	}
+
+	mimeType := essenceOf(parcel.MediaType())
+	if alias, ok := mediaTypeAliases[mimeType]; ok {
+		mimeType = alias
+	}
+
+	var length int
+	{
+		data := UnsafeBytes(parcel)
+
+		mediaType, err := minimizeMediaType(parcel.MediaType())
+		if nil != err {
+			mediaType = parcel.MediaType()
+		}
+
+		length, _ = encodedLen(mediaType, data, ShortestEncoding(data))
+	}
+
+	report := CompatibilityReport{
+		Profile: profile,
+		Length:  length,
+	}
+
+	for _, usage := range allUsages {
+		result := CompatibilityResult{
+			Usage:   usage,
+			Support: SupportYes,
+		}
+
+		for _, rule := range compatibilityRules {
+			if rule.matches(profile, usage, mimeType, length) {
+				result.Support = rule.Support
+				result.Reason = rule.Reason
+				break
+			}
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
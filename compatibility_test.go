@@ -0,0 +1,165 @@
+package dataurl
+
+
+import (
+	"strings"
+	"testing"
+)
+
+
+func TestCompatibility(t *testing.T) {
+
+	png := MustParse(`data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAA=`)
+	js  := MustParse(`data:text/javascript,alert(1)`)
+	big := MustParse(`data:image/svg+xml,` + strings.Repeat("a", legacyIEMaxLength))
+
+	tests := []struct{
+		Parcel   Parcel
+		Profile  Profile
+		Expected map[Usage]Support
+	}{
+		{
+			Parcel:  png,
+			Profile: ProfileModernBrowsers,
+			Expected: map[Usage]Support{
+				UsageImage:      SupportConditional,
+				UsageCSS:        SupportConditional,
+				UsageNavigation: SupportNo,
+				UsageIframe:     SupportConditional,
+				UsageScript:     SupportNo,
+				UsageSVGUse:     SupportNo,
+				UsageDownload:   SupportYes,
+			},
+		},
+		{
+			Parcel:  png,
+			Profile: ProfileEmailClients,
+			Expected: map[Usage]Support{
+				UsageImage:      SupportNo,
+				UsageCSS:        SupportNo,
+				UsageNavigation: SupportNo,
+				UsageIframe:     SupportNo,
+				UsageScript:     SupportNo,
+				UsageSVGUse:     SupportNo,
+				UsageDownload:   SupportNo,
+			},
+		},
+		{
+			Parcel:  png,
+			Profile: ProfileLegacyIE,
+			Expected: map[Usage]Support{
+				UsageImage:      SupportYes,
+				UsageCSS:        SupportYes,
+				UsageNavigation: SupportNo,
+				UsageIframe:     SupportNo,
+				UsageScript:     SupportNo,
+				UsageSVGUse:     SupportNo,
+				UsageDownload:   SupportNo,
+			},
+		},
+		{
+			Parcel:  big,
+			Profile: ProfileLegacyIE,
+			Expected: map[Usage]Support{
+				UsageImage: SupportConditional,
+				UsageCSS:   SupportConditional,
+			},
+		},
+		{
+			Parcel:  png,
+			Profile: ProfileElectron,
+			Expected: map[Usage]Support{
+				UsageImage:      SupportConditional,
+				UsageNavigation: SupportConditional,
+				UsageSVGUse:     SupportNo,
+			},
+		},
+		{
+			Parcel:  js,
+			Profile: ProfileModernBrowsers,
+			Expected: map[Usage]Support{
+				UsageImage:  SupportNo,
+				UsageScript: SupportConditional,
+			},
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		report := Compatibility(test.Parcel, test.Profile)
+
+		if expected, actual := test.Profile, report.Profile; expected != actual {
+			t.Errorf("For test #%d, expected profile %s, but actually got %s.", testNumber, expected, actual)
+			continue
+		}
+		if expected, actual := len(Usages()), len(report.Results); expected != actual {
+			t.Errorf("For test #%d, expected %d results, but actually got %d.", testNumber, expected, actual)
+			continue
+		}
+
+		for usage, expected := range test.Expected {
+			if actual := report.Support(usage); expected != actual {
+				t.Errorf("For test #%d and profile %s, expected %s to be %s, but actually was %s.", testNumber, test.Profile, usage, expected, actual)
+			}
+		}
+
+		for _, result := range report.Results {
+			if SupportYes != result.Support && "" == result.Reason {
+				t.Errorf("For test #%d and profile %s, expected a reason for %s, but there was not one.", testNumber, test.Profile, result.Usage)
+			}
+		}
+	}
+}
+
+
+func TestCompatibilityNil(t *testing.T) {
+	report := Compatibility(nil, ProfileModernBrowsers)
+
+	if expected, actual := len(Usages()), len(report.Results); expected != actual {
+		t.Fatalf("Expected %d results, but actually got %d.", expected, actual)
+	}
+
+	for _, result := range report.Results {
+		if expected, actual := SupportNo, result.Support; expected != actual {
+			t.Errorf("For %s, expected %s, but actually was %s.", result.Usage, expected, actual)
+		}
+		if "" == result.Reason {
+			t.Errorf("For %s, expected a reason, but there was not one.", result.Usage)
+		}
+	}
+}
+
+
+func TestCompatibilityUnknownProfile(t *testing.T) {
+	report := Compatibility(MustParse("data:image/png;base64,iVBORw0KGgo="), Profile(-1))
+
+	if expected, actual := len(Usages()), len(report.Results); expected != actual {
+		t.Fatalf("Expected %d results, but actually got %d.", expected, actual)
+	}
+
+	for _, result := range report.Results {
+		if expected, actual := SupportNo, result.Support; expected != actual {
+			t.Errorf("For %s, expected %s, but actually was %s.", result.Usage, expected, actual)
+		}
+		if expected, actual := reasonUnknownProfile, result.Reason; expected != actual {
+			t.Errorf("For %s, expected reason %q, but actually was %q.", result.Usage, expected, actual)
+		}
+	}
+}
+
+
+func TestUsages(t *testing.T) {
+	usages := Usages()
+	if expected, actual := UsageImage, usages[0]; expected != actual {
+		t.Fatalf("Expected the 1st usage to be %s, but actually was %s.", expected, actual)
+	}
+
+	usages[0] = UsageDownload
+
+	if expected, actual := UsageImage, Usages()[0]; expected != actual {
+		t.Errorf("Expected modifying the returned slice to not affect Usages(), but the 1st usage became %s.", actual)
+	}
+	if expected, actual := UsageImage, Compatibility(MustParse("data:,"), ProfileModernBrowsers).Results[0].Usage; expected != actual {
+		t.Errorf("Expected modifying the returned slice to not affect Compatibility(), but the 1st usage became %s.", actual)
+	}
+}
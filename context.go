@@ -0,0 +1,229 @@
+package dataurl
+
+
+import (
+	"fmt"
+	"strings"
+)
+
+
+// Context is used to specify where (in what kind of document) a data URL is going to be put.
+//
+// A data URL that is valid by itself can still break the document it is put into.
+// For example, a "'" in a data URL put into a CSS url('...'), or "</script>" in a
+// data URL put into a JavaScript string.
+//
+// dataurl.Escape() and dataurl.EncodeFor() use a Context to escape exactly what needs
+// to be escaped for that kind of document.
+type Context int
+
+
+const (
+	// ContextNone means no escaping.
+	ContextNone Context = iota
+
+	// ContextHTMLAttribute is the value of an HTML (or XML) attribute; quoted with either ' or ".
+	// For example: <img src="...">
+	ContextHTMLAttribute
+
+	// ContextCSSURL is the inside of a CSS url(); either quoted with ' or ", or unquoted.
+	// For example: background-image: url(...)
+	ContextCSSURL
+
+	// ContextJSString is the inside of a JavaScript string literal; quoted with ', " or `.
+	// This is also safe inside of an HTML <script> element.
+	ContextJSString
+
+	// ContextMarkdownLink is the destination of a Markdown link or image.
+	// For example: ![alt text](...)
+	ContextMarkdownLink
+)
+
+
+// String returns the name of the context.
+func (context Context) String() string {
+	switch context {
+	case ContextNone:
+		return "none"
+	case ContextHTMLAttribute:
+		return "html-attribute"
+	case ContextCSSURL:
+		return "css-url"
+	case ContextJSString:
+		return "js-string"
+	case ContextMarkdownLink:
+		return "markdown-link"
+	default:
+		return "unknown"
+	}
+}
+
+
+// Escape escapes the data URL 'dataURL' so that it can be put into the kind of document
+// specified by 'context'.
+//
+// Once whatever reads that kind of document unescapes it, the result is 'dataURL' again. Except
+// that a Markdown link destination cannot have a line break (or other control character) in it;
+// so for ContextMarkdownLink those are removed (from base64 encoded contents, where they do not
+// mean anything) or percent encoded. Either way, the result parses to the same Parcel.
+//
+// Example usage:
+//
+//	fmt.Printf(`<img src="%s">`, dataurl.Escape(dataURL, dataurl.ContextHTMLAttribute))
+func Escape(dataURL string, context Context) string {
+	switch context {
+	case ContextHTMLAttribute:
+		return escapeHTMLAttribute(dataURL)
+	case ContextCSSURL:
+		return escapeCSSURL(dataURL)
+	case ContextJSString:
+		return escapeJSString(dataURL)
+	case ContextMarkdownLink:
+		return escapeMarkdownLink(dataURL)
+	default:
+		return dataURL
+	}
+}
+
+
+// EncodeFor is like dataurl.Encode(), except that the data URL returned is escaped
+// (with dataurl.Escape()) for the kind of document specified by 'context'.
+//
+// Example usage:
+//
+//	dataURL, err := dataurl.EncodeFor(dataurl.ContextCSSURL, "image/svg+xml", svg, dataurl.EncodingPercent)
+//	if nil != err {
+//		//@TODO
+//	}
+//
+//	css := fmt.Sprintf("background-image: url('%s');", dataURL)
+func EncodeFor(context Context, mediaType string, data []byte, encoding Encoding) (string, error) {
+	dataURL, err := Encode(mediaType, data, encoding)
+	if nil != err {
+		return "", err
+	}
+
+	return Escape(dataURL, context), nil
+}
+
+
+var htmlAttributeReplacer = strings.NewReplacer(
+	`&`, "&amp;",
+	`"`, "&quot;",
+	`'`, "&#39;",
+	`<`, "&lt;",
+	`>`, "&gt;",
+)
+
+
+func escapeHTMLAttribute(s string) string {
+	return htmlAttributeReplacer.Replace(s)
+}
+
+
+// escapeCSSURL uses CSS escapes. A backslash followed by punctuation is that
+// punctuation. And a backslash followed by hex digits (and an optional space)
+// is that code point.
+func escapeCSSURL(s string) string {
+	var builder strings.Builder
+
+	for _, r := range s {
+		switch {
+		case '"' == r, '\'' == r, '(' == r, ')' == r, '\\' == r:
+			builder.WriteByte('\\')
+			builder.WriteRune(r)
+		case r <= ' ', 0x7F == r:
+			// The trailing space ends the escape; so that a hex digit after it
+			// is not taken as part of it.
+			fmt.Fprintf(&builder, `\%x `, r)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
+
+
+func escapeJSString(s string) string {
+	var builder strings.Builder
+
+	for _, r := range s {
+		switch r {
+		case '\\':
+			builder.WriteString(`\\`)
+		case '"':
+			builder.WriteString(`\"`)
+		case '\'':
+			builder.WriteString(`\'`)
+		case '`':
+			builder.WriteString(`\x60`)
+		case '$': // Would start a ${...} in a template literal.
+			builder.WriteString(`\x24`)
+		case '<': // "</script>" and "<!--" would end (or confuse) an HTML <script> element.
+			builder.WriteString(`\x3C`)
+		case '>':
+			builder.WriteString(`\x3E`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\u2028', '\u2029': // Line terminators in (older) JavaScript.
+			fmt.Fprintf(&builder, `\u%04X`, r)
+		default:
+			if r < ' ' || 0x7F == r {
+				fmt.Fprintf(&builder, `\x%02X`, r)
+				continue
+			}
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
+
+
+// escapeMarkdownLink uses backslash escapes (which CommonMark allows for any ASCII
+// punctuation in a link destination). If there are spaces, then the destination is
+// put inside of < and >, since otherwise a space would end it.
+func escapeMarkdownLink(s string) string {
+	var builder strings.Builder
+
+	// Where the base64 encoded contents start (if they are base64 encoded).
+	base64Start := len(s)
+	if strings.HasPrefix(s, dataColon) {
+		if index, base64Encoded := splitDataURL(s); base64Encoded {
+			base64Start = index + len(semicolonBase64Comma)
+		}
+	}
+
+	hasSpace := false
+	for i, r := range s {
+		switch {
+		case base64Start <= i && ('\r' == r || '\n' == r):
+			// The base64 decoder skips line breaks; so dropping them does not change
+			// what the data URL means. (Percent encoding them would; since "%0A" is
+			// not base64.)
+		case '\\' == r, '(' == r, ')' == r, '<' == r, '>' == r, '[' == r, ']' == r:
+			builder.WriteByte('\\')
+			builder.WriteRune(r)
+		case ' ' == r:
+			hasSpace = true
+			builder.WriteRune(r)
+		case r < ' ', 0x7F == r:
+			// A link destination cannot contain a line break (or other control character)
+			// even inside of < and >. These can only (validly) be in the percent encoded
+			// contents of a data URL; where percent encoding them does not change what
+			// they mean.
+			fmt.Fprintf(&builder, `%%%02X`, r)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+
+	if hasSpace {
+		return "<" + builder.String() + ">"
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,209 @@
+package dataurl
+
+
+import (
+	"html"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+
+// unescapeCSS undoes CSS escapes, the way a CSS parser would.
+func unescapeCSS(s string) string {
+	var builder strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if '\\' != s[i] || len(s) <= i+1 {
+			builder.WriteByte(s[i])
+			continue
+		}
+
+		j := i + 1
+		for j < len(s) && j < i+7 && strings.IndexByte("0123456789abcdefABCDEF", s[j]) != -1 {
+			j++
+		}
+		if j == i+1 {
+			builder.WriteByte(s[j])
+			i = j
+			continue
+		}
+
+		n, _ := strconv.ParseUint(s[i+1:j], 16, 32)
+		builder.WriteRune(rune(n))
+		if j < len(s) && ' ' == s[j] {
+			j++
+		}
+		i = j - 1
+	}
+
+	return builder.String()
+}
+
+
+// unescapeJS undoes JavaScript string escapes, the way a JavaScript parser would.
+func unescapeJS(s string) string {
+	var builder strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if '\\' != s[i] || len(s) <= i+1 {
+			builder.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 'n':
+			builder.WriteByte('\n')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'x':
+			n, _ := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			builder.WriteRune(rune(n))
+			i += 2
+		case 'u':
+			n, _ := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			builder.WriteRune(rune(n))
+			i += 4
+		default:
+			builder.WriteByte(s[i])
+		}
+	}
+
+	return builder.String()
+}
+
+
+// unescapeMarkdownLink undoes Markdown link destination escapes, the way a CommonMark parser would.
+func unescapeMarkdownLink(s string) string {
+	if strings.HasPrefix(s, "<") && strings.HasSuffix(s, ">") {
+		s = s[1:len(s)-1]
+	}
+
+	var builder strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if '\\' == s[i] && i+1 < len(s) && strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", s[i+1]) != -1 {
+			i++
+		}
+		builder.WriteByte(s[i])
+	}
+
+	return builder.String()
+}
+
+
+func TestEscapeRoundTrip(t *testing.T) {
+
+	dataURLs := []string{
+		`data:,Hello%20world!`,
+		`data:text/plain;charset=utf-8;base64,VGhpcyBpcyBhIHRlc3Qh`,
+		`data:text/html,<script>alert("hi & bye")</script>`,
+		`data:text/css,a{background:url('x.png')}`,
+		`data:image/svg+xml,<svg xmlns='http://www.w3.org/2000/svg'><text>(1) [2] $3 \4 `+"`5`"+`</text></svg>`,
+		`data:text/plain;name="a b.txt",spaces and )parens(`,
+		"data:,line\u2028separator",
+	}
+
+	unescapers := map[Context]func(string) string{
+		ContextNone:          func(s string) string { return s },
+		ContextHTMLAttribute: html.UnescapeString,
+		ContextCSSURL:        unescapeCSS,
+		ContextJSString:      unescapeJS,
+		ContextMarkdownLink:  unescapeMarkdownLink,
+	}
+
+	// What each context must not have in it (unescaped), after escaping.
+	forbidden := map[Context][]string{
+		ContextHTMLAttribute: {`"`, `'`, `<`, `>`},
+		ContextCSSURL:        {"\n", "\t"},
+		ContextJSString:      {`</`, "\n", "`", `${`, "\u2028", "\u2029"},
+		ContextMarkdownLink:  {"\n"},
+	}
+
+
+	for testNumber, dataURL := range dataURLs {
+		for context, unescape := range unescapers {
+			escaped := Escape(dataURL, context)
+
+			for _, s := range forbidden[context] {
+				if strings.Contains(escaped, s) {
+					t.Errorf("For test #%d and context %s, did not expect the escaped data URL to contain %q, but it did: %q", testNumber, context, s, escaped)
+				}
+			}
+
+			if expected, actual := dataURL, unescape(escaped); expected != actual {
+				t.Errorf("For test #%d and context %s, expected the unescaped data URL to be %q, but actually was %q.\nEscaped: %q", testNumber, context, expected, actual, escaped)
+				continue
+			}
+
+			if _, err := Parse(unescape(escaped)); nil != err {
+				t.Errorf("For test #%d and context %s, did not expect an error when parsing, but actually got one: %v", testNumber, context, err)
+				continue
+			}
+		}
+	}
+}
+
+
+func TestEscapeMarkdownLinkLineBreaks(t *testing.T) {
+
+	tests := []struct{
+		DataURL  string
+		Expected string
+	}{
+		{
+			DataURL:  "data:;base64,QUFB\nQUFB",
+			Expected: `data:;base64,QUFBQUFB`,
+		},
+		{
+			DataURL:  "data:image/png;base64,iVBORw0K\r\nGgo=\r\n",
+			Expected: `data:image/png;base64,iVBORw0KGgo=`,
+		},
+		{
+			DataURL:  "data:,line\nbreak",
+			Expected: `data:,line%0Abreak`,
+		},
+		{
+			DataURL:  "data:text/plain;name=\"a b.txt\";base64,QUFB\nQUFB",
+			Expected: `<data:text/plain;name="a b.txt";base64,QUFBQUFB>`,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		escaped := Escape(test.DataURL, ContextMarkdownLink)
+
+		if expected, actual := test.Expected, escaped; expected != actual {
+			t.Errorf("For test #%d, expected %q, but actually got %q.", testNumber, expected, actual)
+			continue
+		}
+
+		parcel, err := Parse(unescapeMarkdownLink(escaped))
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error when parsing, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if !Equal(MustParse(test.DataURL), parcel) {
+			t.Errorf("For test #%d, expected the escaped data URL %q to parse to the same parcel as %q, but it did not.", testNumber, escaped, test.DataURL)
+			continue
+		}
+	}
+}
+
+
+func TestEscapeCSSURLParens(t *testing.T) {
+	dataURL, err := EncodeFor(ContextCSSURL, "image/svg+xml", []byte(`<svg><path d="M0 0"/></svg>`), EncodingPercent)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if strings.ContainsAny(strings.ReplaceAll(strings.ReplaceAll(dataURL, `\(`, ``), `\)`, ``), `()'"`) {
+		t.Errorf("Did not expect any unescaped quotes or parentheses, but there were: %q", dataURL)
+	}
+
+	if expected, actual := `<svg><path d="M0 0"/></svg>`, MustParse(unescapeCSS(dataURL)).String(); expected != actual {
+		t.Errorf("Expected the content to be %q, but actually was %q.", expected, actual)
+	}
+}
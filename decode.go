@@ -0,0 +1,139 @@
+package dataurl
+
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"unsafe"
+)
+
+
+// base64Decode decodes the base64 encoded 'encoded'.
+//
+// It is like base64.StdEncoding.DecodeString(), except that the result is a string,
+// and it is decoded (exactly once) into memory that is allocated (exactly once) to
+// the right size.
+func base64Decode(encoded string) (string, error) {
+	if "" == encoded {
+		return "", nil
+	}
+
+	// base64.StdEncoding.Decode() only reads from 'src', so it is OK for it to
+	// share memory with the (immutable) string 'encoded'.
+	src := unsafe.Slice(unsafe.StringData(encoded), len(encoded))
+
+	dst := make([]byte, base64.StdEncoding.DecodedLen(len(encoded)))
+
+	n, err := base64.StdEncoding.Decode(dst, src)
+	if nil != err {
+		return "", err
+	}
+	if n < 1 {
+		return "", nil
+	}
+
+	// Nothing else references 'dst', so it is OK for the returned string to
+	// share memory with it.
+	return unsafe.String(&dst[0], n), nil
+}
+
+
+// percentDecode decodes the percent encoded 'encoded'.
+//
+// It is like url.QueryUnescape() (including turning a '+' into a space), except
+// that if there isn't anything to decode, then it returns 'encoded' itself (rather
+// than a copy of it); and otherwise it allocates memory only once.
+func percentDecode(encoded string) (string, error) {
+	n := 0
+	for i := 0; i < len(encoded); i++ {
+		switch encoded[i] {
+		case '%':
+			if len(encoded) <= i+2 || !isHex(encoded[i+1]) || !isHex(encoded[i+2]) {
+				escape := encoded[i:]
+				if 3 < len(escape) {
+					escape = escape[:3]
+				}
+				return "", escapeError{url.EscapeError(escape), i}
+			}
+			n++
+			i += 2
+		case '+':
+			n++
+		}
+	}
+
+	if 0 == n {
+		return encoded, nil
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(encoded) - 2*strings.Count(encoded, "%"))
+
+	for i := 0; i < len(encoded); i++ {
+		switch b := encoded[i]; b {
+		case '%':
+			builder.WriteByte(unhex(encoded[i+1])<<4 | unhex(encoded[i+2]))
+			i += 2
+		case '+':
+			builder.WriteByte(' ')
+		default:
+			builder.WriteByte(b)
+		}
+	}
+
+	return builder.String(), nil
+}
+
+
+// base64DecodedLen returns how many bytes the base64 encoded 'encoded' decodes to; without decoding it.
+//
+// It starts from base64.StdEncoding.DecodedLen(), and does not count the line breaks (which the
+// decoder skips) or the padding. If 'encoded' is not valid base64, then it is only an estimate.
+func base64DecodedLen(encoded string) int64 {
+	n := len(encoded) - strings.Count(encoded, "\r") - strings.Count(encoded, "\n")
+
+	return int64(base64.StdEncoding.DecodedLen(n) - strings.Count(encoded, "="))
+}
+
+
+// percentDecodedLen returns how many bytes the percent encoded 'encoded' decodes to; without decoding it.
+//
+// It is the length of 'encoded', less 2 bytes for each percent escape. If 'encoded' is not valid, then it
+// is only an estimate.
+func percentDecodedLen(encoded string) int64 {
+	return int64(len(encoded) - 2*strings.Count(encoded, "%"))
+}
+
+
+// escapeError is a bad percent escape; at 'offset' in what percentDecode() was decoding.
+type escapeError struct {
+	url.EscapeError
+	offset int
+}
+
+
+func isHex(b byte) bool {
+	switch {
+	case '0' <= b && b <= '9':
+		return true
+	case 'a' <= b && b <= 'f':
+		return true
+	case 'A' <= b && b <= 'F':
+		return true
+	default:
+		return false
+	}
+}
+
+
+func unhex(b byte) byte {
+	switch {
+	case '0' <= b && b <= '9':
+		return b - '0'
+	case 'a' <= b && b <= 'f':
+		return b - 'a' + 10
+	default:
+		return b - 'A' + 10
+	}
+}
@@ -0,0 +1,326 @@
+package dataurl
+
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/reiver/go-dataurl/internal/zstd"
+)
+
+
+const (
+	contentEncodingParameter = "content-encoding"
+
+	// DefaultMaxDecompressedSize is the default for DecompressionLimits.MaxSize.
+	DefaultMaxDecompressedSize = 32 * 1024 * 1024
+
+	// DefaultMaxDecompressionRatio is the default for DecompressionLimits.MaxRatio.
+	DefaultMaxDecompressionRatio = 100
+
+	// ratioGracePeriod is how many bytes may be decompressed before the ratio
+	// limit is enforced. (Small inputs, legitimately, can have large ratios.)
+	ratioGracePeriod = 64 * 1024
+)
+
+
+// DecompressionLimits are used to stop "zip bombs" — compressed content that
+// decompresses into something huge.
+//
+// The zero value of DecompressionLimits uses the defaults.
+type DecompressionLimits struct {
+	// MaxSize is the most bytes the decompressed content may be.
+	// If zero, then DefaultMaxDecompressedSize is used.
+	//
+	// Going over it is a TooLargeComplainer error.
+	MaxSize int64
+
+	// MaxRatio is the most the decompressed size may be, relative to the
+	// compressed size. If zero, then DefaultMaxDecompressionRatio is used.
+	//
+	// Going over it is a TooCompressedComplainer error.
+	MaxRatio int64
+}
+
+
+func (limits DecompressionLimits) maxSize() int64 {
+	if limits.MaxSize <= 0 {
+		return DefaultMaxDecompressedSize
+	}
+	return limits.MaxSize
+}
+
+
+func (limits DecompressionLimits) maxRatio() int64 {
+	if limits.MaxRatio <= 0 {
+		return DefaultMaxDecompressionRatio
+	}
+	return limits.MaxRatio
+}
+
+
+// ContentEncoding returns how the contents of 'parcel' is compressed.
+//
+// This is either from a "content-encoding" parameter on the media type
+// (ex: "data:application/json;content-encoding=gzip;base64,...") or from the
+// media type itself (ex: "data:application/gzip;base64,...").
+//
+// It returns one of "gzip", "zlib", "deflate", "zstd", (some other value of
+// a "content-encoding" parameter), or the empty string if it isn't compressed.
+func ContentEncoding(parcel Parcel) string {
+	mimeType, params, err := mime.ParseMediaType(parcel.MediaType())
+	if nil != err {
+		return ""
+	}
+
+	if encoding, ok := params[contentEncodingParameter]; ok {
+		encoding = strings.ToLower(encoding)
+		switch encoding {
+		case "x-gzip":
+			return "gzip"
+		default:
+			return encoding
+		}
+	}
+
+	switch mimeType {
+	case "application/gzip", "application/x-gzip":
+		return "gzip"
+	case "application/zlib":
+		return "zlib"
+	case "application/zstd":
+		return "zstd"
+	default:
+		return ""
+	}
+}
+
+
+// DecompressingReader returns an io.Reader that decompresses the contents of 'parcel'
+// as it is read. (If the contents of 'parcel' is not compressed, then the io.Reader
+// just returns the contents as is.)
+//
+// gzip, zlib, deflate and zstd (without a dictionary) are supported.
+//
+// Reading returns a TooLargeComplainer error, once more than 'limits' allows (by MaxSize)
+// has been decompressed. And returns a TooCompressedComplainer error, once more than 'limits'
+// allows (by MaxRatio) has been.
+func DecompressingReader(parcel Parcel, limits DecompressionLimits) (io.Reader, error) {
+	compressed := UnsafeBytes(parcel)
+
+	var reader io.Reader
+	switch encoding := ContentEncoding(parcel); encoding {
+	case "":
+		return bytes.NewReader(compressed), nil
+	case "gzip":
+		r, err := gzip.NewReader(bytes.NewReader(compressed))
+		if nil != err {
+			return nil, newSyntaxErrorComplainer("bad gzip content: %s", err)
+		}
+		reader = r
+	case "zlib":
+		r, err := zlib.NewReader(bytes.NewReader(compressed))
+		if nil != err {
+			return nil, newSyntaxErrorComplainer("bad zlib content: %s", err)
+		}
+		reader = r
+	case "deflate":
+		// As with HTTP, "deflate" content is sometimes zlib wrapped,
+		// and sometimes raw deflate. So we check which it is.
+		if isZlibHeader(compressed) {
+			r, err := zlib.NewReader(bytes.NewReader(compressed))
+			if nil != err {
+				return nil, newSyntaxErrorComplainer("bad deflate content: %s", err)
+			}
+			reader = r
+		} else {
+			reader = flate.NewReader(bytes.NewReader(compressed))
+		}
+	case "zstd":
+		reader = zstd.NewReader(bytes.NewReader(compressed))
+	default:
+		return nil, newBadMediaTypeComplainer(fmt.Errorf("content encoding %q is not supported", encoding))
+	}
+
+	limited := limitedReader{
+		reader:         reader,
+		compressedSize: int64(len(compressed)),
+		maxSize:        limits.maxSize(),
+		maxRatio:       limits.maxRatio(),
+	}
+
+	return &limited, nil
+}
+
+
+// Decompress returns a new Parcel with the decompressed contents of 'parcel'.
+//
+// The "content-encoding" parameter is removed from the media type of the returned Parcel.
+// And if the media type was a compression type (such as "application/gzip"), then the
+// media type of the returned Parcel is "application/octet-stream".
+//
+// If the contents of 'parcel' is not compressed, then 'parcel' is returned.
+//
+// Example usage:
+//
+//	parcel, err := dataurl.Parse("data:application/json;content-encoding=gzip;base64,H4sIAAAAAAAC/6pWSlSyUkpSqgUMAJxc9msJAAAA")
+//	if nil != err {
+//		//@TODO
+//	}
+//
+//	parcel, err = dataurl.Decompress(parcel, dataurl.DecompressionLimits{})
+//	if nil != err {
+//		//@TODO
+//	}
+//
+//	fmt.Println(parcel.String()) // {"a":"b"}
+func Decompress(parcel Parcel, limits DecompressionLimits) (Parcel, error) {
+	if "" == ContentEncoding(parcel) {
+		return parcel, nil
+	}
+
+	reader, err := DecompressingReader(parcel, limits)
+	if nil != err {
+		return nil, err
+	}
+
+	var buffer strings.Builder
+	if _, err := io.Copy(&buffer, reader); nil != err {
+		switch err.(type) {
+		case BadRequestComplainer, InternalErrorComplainer:
+			return nil, err
+		default:
+			return nil, newSyntaxErrorComplainer("could not decompress content: %s", err)
+		}
+	}
+
+	mediaType, err := decompressedMediaType(parcel.MediaType())
+	if nil != err {
+		return nil, err
+	}
+
+	decompressed := newParcel()
+	decompressed.mediaType = mediaType
+	decompressed.content = buffer.String()
+
+	return decompressed, nil
+}
+
+
+// EncodeCompressed is like dataurl.Encode() with dataurl.EncodingBase64, except that
+// if gzip compressing 'data' makes the data URL shorter, then it does that, and adds
+// the "content-encoding=gzip" parameter to the media type.
+//
+// dataurl.Decompress() can be used to get back 'data'.
+func EncodeCompressed(mediaType string, data []byte) (string, error) {
+	uncompressed, err := Encode(mediaType, data, EncodingBase64)
+	if nil != err {
+		return "", err
+	}
+
+	// Compressed content is always base64 encoded, so if the base64 encoding of
+	// the compressed content can't be shorter, then don't bother.
+	const gzipOverhead = 18
+	if base64.StdEncoding.EncodedLen(len(data)) <= base64.StdEncoding.EncodedLen(gzipOverhead) {
+		return uncompressed, nil
+	}
+
+	var compressed bytes.Buffer
+	{
+		writer, err := gzip.NewWriterLevel(&compressed, gzip.BestCompression)
+		if nil != err {
+			return "", newInternalErrorComplainer("could not create gzip writer: %s", err)
+		}
+		if _, err := writer.Write(data); nil != err {
+			return "", newInternalErrorComplainer("could not gzip content: %s", err)
+		}
+		if err := writer.Close(); nil != err {
+			return "", newInternalErrorComplainer("could not gzip content: %s", err)
+		}
+	}
+
+	if "" == mediaType {
+		mediaType = defaultMediaType
+	}
+	compressedMediaType, err := rewriteMediaType(mediaType, func(mimeType string, params map[string]string) string {
+		params[contentEncodingParameter] = "gzip"
+		return mimeType
+	})
+	if nil != err {
+		return "", err
+	}
+
+	dataURL, err := Encode(compressedMediaType, compressed.Bytes(), EncodingBase64)
+	if nil != err {
+		return "", err
+	}
+
+	if len(uncompressed) <= len(dataURL) {
+		return uncompressed, nil
+	}
+
+	return dataURL, nil
+}
+
+
+// decompressedMediaType returns what the media type 'mediaType' (of compressed content)
+// becomes, once the content is decompressed.
+func decompressedMediaType(mediaType string) (string, error) {
+	formatted, err := rewriteMediaType(mediaType, func(mimeType string, params map[string]string) string {
+		if _, ok := params[contentEncodingParameter]; !ok {
+			return "application/octet-stream"
+		}
+
+		delete(params, contentEncodingParameter)
+		return mimeType
+	})
+	if nil != err {
+		return "", err
+	}
+
+	return sanitizeMediaType(formatted)
+}
+
+
+// isZlibHeader returns whether 'p' starts with a zlib header.
+func isZlibHeader(p []byte) bool {
+	if len(p) < 2 {
+		return false
+	}
+
+	return 8 == p[0]&0x0F && 0 == (uint16(p[0])<<8|uint16(p[1]))%31
+}
+
+
+// limitedReader returns a TooLargeComplainer (or a TooCompressedComplainer) once more than is
+// allowed has been read.
+type limitedReader struct {
+	reader         io.Reader
+	compressedSize int64
+	read           int64
+	maxSize        int64
+	maxRatio       int64
+}
+
+
+func (receiver *limitedReader) Read(p []byte) (int, error) {
+	n, err := receiver.reader.Read(p)
+	receiver.read += int64(n)
+
+	if receiver.maxSize < receiver.read {
+		return n, newTooLargeComplainer(receiver.maxSize, "decompressed content is more than %d bytes", receiver.maxSize)
+	}
+
+	if ratioGracePeriod < receiver.read && receiver.maxRatio*max(1, receiver.compressedSize) < receiver.read {
+		return n, newTooCompressedComplainer(receiver.maxRatio, "decompressed content is more than %d times the size of the compressed content", receiver.maxRatio)
+	}
+
+	return n, err
+}
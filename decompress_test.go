@@ -0,0 +1,266 @@
+package dataurl
+
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+)
+
+
+func compressForTest(t *testing.T, encoding string, data []byte) string {
+	var buffer bytes.Buffer
+
+	var writer io.WriteCloser
+	switch encoding {
+	case "gzip":
+		writer = gzip.NewWriter(&buffer)
+	case "zlib":
+		writer = zlib.NewWriter(&buffer)
+	case "deflate":
+		w, err := flate.NewWriter(&buffer, flate.DefaultCompression)
+		if nil != err {
+			t.Fatalf("Could not create flate writer: %v", err)
+		}
+		writer = w
+	default:
+		t.Fatalf("Unknown encoding %q", encoding)
+	}
+
+	writer.Write(data)
+	writer.Close()
+
+	return base64.StdEncoding.EncodeToString(buffer.Bytes())
+}
+
+
+func TestDecompress(t *testing.T) {
+	const content = `{"message":"Hello world!"}`
+
+	tests := []struct{
+		DataURL           string
+		ExpectedMediaType string
+	}{
+		{
+			DataURL:           "data:application/json;content-encoding=gzip;base64," + compressForTest(t, "gzip", []byte(content)),
+			ExpectedMediaType: "application/json;charset=US-ASCII",
+		},
+		{
+			DataURL:           "data:application/json;charset=utf-8;content-encoding=zlib;base64," + compressForTest(t, "zlib", []byte(content)),
+			ExpectedMediaType: "application/json;charset=utf-8",
+		},
+		{
+			DataURL:           "data:application/json;content-encoding=deflate;base64," + compressForTest(t, "zlib", []byte(content)),
+			ExpectedMediaType: "application/json;charset=US-ASCII",
+		},
+		{
+			DataURL:           "data:application/json;content-encoding=deflate;base64," + compressForTest(t, "deflate", []byte(content)),
+			ExpectedMediaType: "application/json;charset=US-ASCII",
+		},
+		{
+			// zstd -c
+			DataURL:           "data:application/json;content-encoding=zstd;base64,KLUv/QRY0QAAeyJtZXNzYWdlIjoiSGVsbG8gd29ybGQhIn33IF50",
+			ExpectedMediaType: "application/json;charset=US-ASCII",
+		},
+		{
+			DataURL:           "data:application/zstd;base64,KLUv/QRY0QAAeyJtZXNzYWdlIjoiSGVsbG8gd29ybGQhIn33IF50",
+			ExpectedMediaType: "application/octet-stream;charset=US-ASCII",
+		},
+		{
+			DataURL:           "data:application/gzip;base64," + compressForTest(t, "gzip", []byte(content)),
+			ExpectedMediaType: "application/octet-stream;charset=US-ASCII",
+		},
+		{
+			DataURL:           "data:application/json;base64," + base64.StdEncoding.EncodeToString([]byte(content)),
+			ExpectedMediaType: "application/json;charset=US-ASCII",
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		parcel, err := Decompress(MustParse(test.DataURL), DecompressionLimits{})
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if expected, actual := content, parcel.String(); expected != actual {
+			t.Errorf("For test #%d, expected content to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+		if expected, actual := test.ExpectedMediaType, parcel.MediaType(); expected != actual {
+			t.Errorf("For test #%d, expected media type to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+	}
+}
+
+
+// zstdBomb is 10 MiB of zeros; compressed with "zstd -19 -c".
+const zstdBomb = "KLUv/QRoTAAACAABAPz/ORACAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAgAQAAIAEAACABAAAwAQAHCbOFE="
+
+
+func TestDecompressLimits(t *testing.T) {
+	gzipBomb := "data:text/plain;content-encoding=gzip;base64," + compressForTest(t, "gzip", make([]byte, 10*1024*1024))
+
+	tests := []struct{
+		DataURL          string
+		Limits           DecompressionLimits
+		ExpectedMaxSize  int64
+		ExpectedMaxRatio int64
+	}{
+		{
+			DataURL:          gzipBomb,
+			Limits:           DecompressionLimits{},
+			ExpectedMaxRatio: DefaultMaxDecompressionRatio,
+		},
+		{
+			DataURL:          gzipBomb,
+			Limits:           DecompressionLimits{MaxSize: 1024, MaxRatio: 1000000},
+			ExpectedMaxSize:  1024,
+		},
+		{
+			DataURL:          "data:text/plain;content-encoding=zstd;base64," + zstdBomb,
+			Limits:           DecompressionLimits{},
+			ExpectedMaxRatio: DefaultMaxDecompressionRatio,
+		},
+		{
+			DataURL:          "data:text/plain;content-encoding=zstd;base64," + zstdBomb,
+			Limits:           DecompressionLimits{MaxSize: 1024, MaxRatio: 1000000},
+			ExpectedMaxSize:  1024,
+		},
+		{
+			DataURL:          gzipBomb,
+			Limits:           DecompressionLimits{MaxSize: 1024 * 1024, MaxRatio: 1000000},
+			ExpectedMaxSize:  1024 * 1024,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		_, err := Decompress(MustParse(test.DataURL), test.Limits)
+		if nil == err {
+			t.Errorf("For test #%d, expected an error, but actually did not get one.", testNumber)
+			continue
+		}
+
+		switch complainer := err.(type) {
+		case TooLargeComplainer:
+			if expected, actual := test.ExpectedMaxSize, complainer.Limit(); expected != actual {
+				t.Errorf("For test #%d, expected a TooLargeComplainer with a limit of %d bytes, but actually got one with %d: %v", testNumber, expected, actual, err)
+				continue
+			}
+		case TooCompressedComplainer:
+			if expected, actual := test.ExpectedMaxRatio, complainer.MaxRatio(); expected != actual {
+				t.Errorf("For test #%d, expected a TooCompressedComplainer with a max ratio of %d, but actually got one with %d: %v", testNumber, expected, actual, err)
+				continue
+			}
+		default:
+			t.Errorf("For test #%d, expected the error to be a TooLargeComplainer or a TooCompressedComplainer, but actually was %T: %v", testNumber, err, err)
+			continue
+		}
+
+		if _, ok := err.(BadRequestComplainer); !ok {
+			t.Errorf("For test #%d, expected the error to be a BadRequestComplainer, but actually was %T: %v", testNumber, err, err)
+			continue
+		}
+	}
+
+	if _, err := Decompress(MustParse("data:application/json;content-encoding=br;base64,CwGAe30D"), DecompressionLimits{}); nil == err {
+		t.Errorf("Expected an error for an unsupported content encoding, but actually did not get one.")
+	} else if _, ok := err.(BadMediaTypeComplainer); !ok {
+		t.Errorf("Expected the error for an unsupported content encoding to be a BadMediaTypeComplainer, but actually was %T: %v", err, err)
+	}
+
+	if _, err := Decompress(MustParse("data:application/json;content-encoding=zstd;base64,KLUv/QRY0QAAeyJt"), DecompressionLimits{}); nil == err {
+		t.Errorf("Expected an error for truncated zstd content, but actually did not get one.")
+	} else if _, ok := err.(SyntaxErrorComplainer); !ok {
+		t.Errorf("Expected the error for truncated zstd content to be a SyntaxErrorComplainer, but actually was %T: %v", err, err)
+	}
+}
+
+
+func TestParseConfigDecompress(t *testing.T) {
+	const content = `{"a":"b"}`
+
+	dataURL := "data:application/json;content-encoding=gzip;base64," + compressForTest(t, "gzip", []byte(content))
+
+	parcel, err := ParseConfig{Decompress: true}.Parse(dataURL)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+	if expected, actual := content, parcel.String(); expected != actual {
+		t.Errorf("Expected content %q, but actually got %q.", expected, actual)
+	}
+	if expected, actual := "application/json;charset=US-ASCII", parcel.MediaType(); expected != actual {
+		t.Errorf("Expected media type %q, but actually got %q.", expected, actual)
+	}
+
+	parcel, err = ParseConfig{}.Parse(dataURL)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+	if expected, actual := "gzip", ContentEncoding(parcel); expected != actual {
+		t.Errorf("Expected the zero value of ParseConfig to not decompress, but the content encoding was %q.", actual)
+	}
+
+	_, err = ParseConfig{Decompress: true, DecompressionLimits: DecompressionLimits{MaxSize: 4}}.Parse(dataURL)
+	if _, ok := err.(TooLargeComplainer); !ok {
+		t.Errorf("Expected a TooLargeComplainer, but actually got %T: %v", err, err)
+	}
+
+	if _, err := (ParseConfig{Decompress: true}).Parse("data:,%zz"); nil == err {
+		t.Errorf("Expected an error for a bad data URL, but actually did not get one.")
+	}
+}
+
+
+func TestEncodeCompressed(t *testing.T) {
+
+	tests := []struct{
+		MediaType          string
+		Data               string
+		ExpectedCompressed bool
+	}{
+		{
+			MediaType:          "application/json",
+			Data:               `{"a":"b"}`,
+			ExpectedCompressed: false,
+		},
+		{
+			MediaType:          "image/svg+xml",
+			Data:               `<svg xmlns="http://www.w3.org/2000/svg">` + strings.Repeat(`<rect width="10" height="10"/>`, 50) + `</svg>`,
+			ExpectedCompressed: true,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		dataURL, err := EncodeCompressed(test.MediaType, []byte(test.Data))
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		parcel := MustParse(dataURL)
+		if expected, actual := test.ExpectedCompressed, "gzip" == ContentEncoding(parcel); expected != actual {
+			t.Errorf("For test #%d, expected compressed to be %t, but actually was %t.\nData URL: %q", testNumber, expected, actual, dataURL)
+			continue
+		}
+
+		decompressed, err := Decompress(parcel, DecompressionLimits{})
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error when decompressing, but actually got one: %v", testNumber, err)
+			continue
+		}
+		if expected, actual := test.Data, decompressed.String(); expected != actual {
+			t.Errorf("For test #%d, expected content to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+	}
+}
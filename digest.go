@@ -0,0 +1,168 @@
+package dataurl
+
+
+import (
+	"crypto"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"mime"
+	"strings"
+)
+
+
+// integrityHashes are the hash algorithms that Subresource Integrity (SRI) supports,
+// from weakest to strongest.
+var integrityHashes = []struct{
+	Name string
+	Hash crypto.Hash
+}{
+	{"sha256", crypto.SHA256},
+	{"sha384", crypto.SHA384},
+	{"sha512", crypto.SHA512},
+}
+
+
+const integrityParameter = "integrity"
+
+
+// Digest returns the hash of the contents of 'parcel', using the hash algorithm 'hash'.
+// This is useful as a key for de-duplicating and caching.
+//
+// Example usage:
+//
+//	digest, err := dataurl.Digest(parcel, crypto.SHA256)
+//	if nil != err {
+//		//@TODO
+//	}
+//
+// SHA-256, SHA-384 and SHA-512 are always available. Others are available if their
+// package has been imported (ex: crypto/sha1). If 'hash' is not available, then Digest
+// returns a BadRequestComplainer.
+func Digest(parcel Parcel, hash crypto.Hash) ([]byte, error) {
+	if !hash.Available() {
+		return nil, newBadRequestComplainer("hash algorithm %v is not available", hash)
+	}
+
+	hasher := hash.New()
+	hasher.Write(UnsafeBytes(parcel))
+
+	return hasher.Sum(nil), nil
+}
+
+
+// Integrity returns a Subresource Integrity (SRI) string for the contents of 'parcel', using
+// the hash algorithm 'hash'; which must be one that SRI supports: crypto.SHA256, crypto.SHA384
+// or crypto.SHA512. For any other hash algorithm, Integrity returns a BadRequestComplainer.
+//
+// For example, for the data URL "data:,Hello%20world!" and crypto.SHA384 this returns:
+// "sha384-hiVfosNuSzCWnq4X3DTHcsvr38WLWEA5AL6HYU6xo0uHgCY/JV615lypu7hkHMz+".
+//
+// Example usage:
+//
+//	integrity, err := dataurl.Integrity(parcel, crypto.SHA384)
+//	if nil != err {
+//		//@TODO
+//	}
+//
+//	fmt.Printf(`<script src="%s" integrity="%s"></script>`, dataURL, integrity)
+func Integrity(parcel Parcel, hash crypto.Hash) (string, error) {
+	for _, integrityHash := range integrityHashes {
+		if integrityHash.Hash != hash {
+			continue
+		}
+
+		digest, err := Digest(parcel, hash)
+		if nil != err {
+			return "", err
+		}
+
+		return integrityHash.Name + "-" + base64.StdEncoding.EncodeToString(digest), nil
+	}
+
+	return "", newBadRequestComplainer("hash algorithm %v is not supported by Subresource Integrity", hash)
+}
+
+
+// VerifyIntegrity checks the contents of 'parcel' against the Subresource Integrity (SRI)
+// string 'integrity'; and returns an IntegrityComplainer error if they do not match.
+//
+// As with SRI, 'integrity' can have more than one (space separated) hash in it. Only the
+// hashes that use the strongest of the algorithms are used; and the contents only has to
+// match one of them.
+//
+// If 'integrity' is the empty string, then the "integrity" parameter of the media type
+// of 'parcel' is used. For example:
+//
+//	data:text/plain;integrity="sha256-wFNeS+K3n/2TKRMFQ2v4iTFOSj+uwF7P/Lt98xrZ5Ro=",Hello%20world!
+//
+// Example usage:
+//
+//	err := dataurl.VerifyIntegrity(parcel, "sha256-wFNeS+K3n/2TKRMFQ2v4iTFOSj+uwF7P/Lt98xrZ5Ro=")
+//	if nil != err {
+//		//@TODO
+//	}
+func VerifyIntegrity(parcel Parcel, integrity string) error {
+	if "" == integrity {
+		_, params, err := mime.ParseMediaType(parcel.MediaType())
+		if nil != err {
+			return newBadMediaTypeComplainer(err)
+		}
+
+		integrity = params[integrityParameter]
+		if "" == integrity {
+			return newIntegrityComplainer("no integrity value to check against")
+		}
+	}
+
+	// Figure out the strongest algorithm used, and collect its hashes.
+	strongest := -1
+	var expected [][]byte
+	for _, token := range strings.Fields(integrity) {
+		// Anything after a "?" is an option, which is ignored.
+		if index := strings.IndexByte(token, '?'); -1 != index {
+			token = token[:index]
+		}
+
+		index := strings.IndexByte(token, '-')
+		if -1 == index {
+			continue
+		}
+		name, encoded := strings.ToLower(token[:index]), token[index+1:]
+
+		for strength, integrityHash := range integrityHashes {
+			if integrityHash.Name != name || strength < strongest {
+				continue
+			}
+
+			digest, err := base64.StdEncoding.DecodeString(encoded)
+			if nil != err {
+				continue
+			}
+
+			if strongest < strength {
+				strongest = strength
+				expected = nil
+			}
+			expected = append(expected, digest)
+		}
+	}
+
+	if strongest < 0 {
+		return newIntegrityComplainer("no supported hash algorithm in integrity value %q", integrity)
+	}
+
+	actual, err := Digest(parcel, integrityHashes[strongest].Hash)
+	if nil != err {
+		return err
+	}
+
+	for _, digest := range expected {
+		if 1 == subtle.ConstantTimeCompare(digest, actual) {
+			return nil
+		}
+	}
+
+	return newIntegrityComplainer("contents does not match integrity value %q", integrity)
+}
@@ -0,0 +1,171 @@
+package dataurl
+
+
+import (
+	"crypto"
+	"encoding/hex"
+	"testing"
+)
+
+
+func TestParcelDigest(t *testing.T) {
+	parcel := MustParse("data:,Hello%20world!")
+
+	tests := []struct{
+		Hash     crypto.Hash
+		Expected string
+	}{
+		{
+			Hash:     crypto.SHA256,
+			Expected: "c0535e4be2b79ffd93291305436bf889314e4a3faec05ecffcbb7df31ad9e51a",
+		},
+		{
+			Hash:     crypto.SHA512,
+			Expected: "f6cde2a0f819314cdde55fc227d8d7dae3d28cc556222a0a8ad66d91ccad4aad6094f517a2182360c9aacf6a3dc323162cb6fd8cdffedb0fe038f55e85ffb5b6",
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		digest, err := Digest(parcel, test.Hash)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if expected, actual := test.Expected, hex.EncodeToString(digest); expected != actual {
+			t.Errorf("For test #%d, expected digest to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+	}
+
+	if _, err := Digest(parcel, crypto.Hash(0)); nil == err {
+		t.Errorf("Expected an error for an unavailable hash, but actually did not get one.")
+	} else if _, ok := err.(BadRequestComplainer); !ok {
+		t.Errorf("Expected the error for an unavailable hash to be a BadRequestComplainer, but actually was %T: %v", err, err)
+	}
+}
+
+
+func TestVerifyIntegrity(t *testing.T) {
+
+	tests := []struct{
+		DataURL   string
+		Integrity string
+	}{
+		{
+			DataURL:   "data:,Hello%20world!",
+			Integrity: "sha256-wFNeS+K3n/2TKRMFQ2v4iTFOSj+uwF7P/Lt98xrZ5Ro=",
+		},
+		{
+			DataURL:   "data:,Hello%20world!",
+			Integrity: "sha384-hiVfosNuSzCWnq4X3DTHcsvr38WLWEA5AL6HYU6xo0uHgCY/JV615lypu7hkHMz+",
+		},
+		{
+			// Only the strongest algorithm (sha512) is used; the (wrong) sha256 hash is ignored.
+			DataURL:   "data:,Hello%20world!",
+			Integrity: "sha256-AAAA sha512-9s3ioPgZMUzd5V/CJ9jX2uPSjMVWIioKitZtkcytSq1glPUXohgjYMmqz2o9wyMWLLb9jN/+2w/gOPVehf+1tg==?ct=text/plain",
+		},
+		{
+			DataURL:   `data:text/plain;integrity="sha256-wFNeS+K3n/2TKRMFQ2v4iTFOSj+uwF7P/Lt98xrZ5Ro=",Hello%20world!`,
+			Integrity: "",
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		if err := VerifyIntegrity(MustParse(test.DataURL), test.Integrity); nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+	}
+}
+
+
+func TestVerifyIntegrityFail(t *testing.T) {
+
+	tests := []struct{
+		DataURL   string
+		Integrity string
+	}{
+		{
+			DataURL:   "data:,Hello%20world",
+			Integrity: "sha256-wFNeS+K3n/2TKRMFQ2v4iTFOSj+uwF7P/Lt98xrZ5Ro=",
+		},
+		{
+			DataURL:   "data:,Hello%20world!",
+			Integrity: "sha256-wFNeS+K3n/2TKRMFQ2v4iTFOSj+uwF7P/Lt98xrZ5Ro= sha384-AAAA",
+		},
+		{
+			DataURL:   "data:,Hello%20world!",
+			Integrity: "md5-7Qdih1MuhjZehB6Sv8UNjA==",
+		},
+		{
+			DataURL:   "data:,Hello%20world!",
+			Integrity: "",
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		err := VerifyIntegrity(MustParse(test.DataURL), test.Integrity)
+		if nil == err {
+			t.Errorf("For test #%d, expected an error, but actually did not get one.", testNumber)
+			continue
+		}
+		if _, ok := err.(IntegrityComplainer); !ok {
+			t.Errorf("For test #%d, expected the error to be an IntegrityComplainer, but actually was %T: %v", testNumber, err, err)
+			continue
+		}
+	}
+}
+
+
+func TestIntegrity(t *testing.T) {
+	parcel := MustParse("data:,Hello%20world!")
+
+	tests := []struct{
+		Hash     crypto.Hash
+		Expected string
+	}{
+		{
+			Hash:     crypto.SHA256,
+			Expected: "sha256-wFNeS+K3n/2TKRMFQ2v4iTFOSj+uwF7P/Lt98xrZ5Ro=",
+		},
+		{
+			Hash:     crypto.SHA384,
+			Expected: "sha384-hiVfosNuSzCWnq4X3DTHcsvr38WLWEA5AL6HYU6xo0uHgCY/JV615lypu7hkHMz+",
+		},
+		{
+			Hash:     crypto.SHA512,
+			Expected: "sha512-9s3ioPgZMUzd5V/CJ9jX2uPSjMVWIioKitZtkcytSq1glPUXohgjYMmqz2o9wyMWLLb9jN/+2w/gOPVehf+1tg==",
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		actual, err := Integrity(parcel, test.Hash)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if expected := test.Expected; expected != actual {
+			t.Errorf("For test #%d, expected integrity to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+
+		if err := VerifyIntegrity(parcel, actual); nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+	}
+
+	for testNumber, hash := range []crypto.Hash{crypto.Hash(0), crypto.SHA224, crypto.SHA512_256} {
+		_, err := Integrity(parcel, hash)
+		if _, ok := err.(BadRequestComplainer); !ok {
+			t.Errorf("For fail test #%d, expected a BadRequestComplainer, but actually got: %#v", testNumber, err)
+			continue
+		}
+	}
+}
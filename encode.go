@@ -0,0 +1,162 @@
+package dataurl
+
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+
+// Encoding is used to specify how the contents of a data URL are (or should be) encoded.
+//
+// A data URL is either 'base64 encoded' (ex: "data:text/plain;base64,SGVsbG8=") or
+// 'percent encoded' (ex: "data:,Hello%20world!").
+type Encoding int
+
+
+const (
+	EncodingBase64 Encoding = iota
+	EncodingPercent
+)
+
+
+// String returns the name of the encoding, as a human would write it.
+func (encoding Encoding) String() string {
+	switch encoding {
+	case EncodingBase64:
+		return "base64"
+	case EncodingPercent:
+		return "percent"
+	default:
+		return "unknown"
+	}
+}
+
+
+// Encode creates a data URL, with the media type given in parameter 'mediaType', containing
+// the contents of parameter 'data', encoded as specified by parameter 'encoding'.
+//
+// If 'mediaType' is the empty string, then the data URL will not contain an explicit media
+// type; and thus (implicitly) it will be "text/plain;charset=US-ASCII".
+//
+// Example usage:
+//
+//	dataURL, err := dataurl.Encode("text/plain;charset=utf-8", []byte("Hello world!"), dataurl.EncodingPercent)
+//	if nil != err {
+//		//@TODO
+//	}
+//
+//	fmt.Println(dataURL) // dataURL == "data:text/plain;charset=utf-8,Hello%20world!"
+//
+// A parameter value (in 'mediaType') that has a comma, a double quote, or whitespace in it
+// is rejected with a BadMediaTypeComplainer; since it cannot be put into a data URL as is.
+//
+// Any data URL returned by Encode can be parsed with dataurl.Parse().
+func Encode(mediaType string, data []byte, encoding Encoding) (string, error) {
+	mediaType, err := formatMediaType(mediaType)
+	if nil != err {
+		return "", err
+	}
+
+	return encodeFormatted(mediaType, data, encoding)
+}
+
+
+// encodeFormatted is like Encode, except that 'mediaType' must already be formatted
+// (with formatMediaType() or minimizeMediaType()).
+func encodeFormatted(mediaType string, data []byte, encoding Encoding) (string, error) {
+	var buffer bytes.Buffer
+
+	buffer.WriteString(dataColon)
+	buffer.WriteString(mediaType)
+
+	switch encoding {
+	case EncodingBase64:
+		buffer.WriteString(semicolonBase64Comma)
+		buffer.WriteString(base64.StdEncoding.EncodeToString(data))
+	case EncodingPercent:
+		buffer.WriteString(comma)
+		percentEncode(&buffer, data)
+	default:
+		return "", newInternalErrorComplainer("Unknown encoding (%d) passed to dataurl.Encode().", encoding)
+	}
+
+	return buffer.String(), nil
+}
+
+
+// MustEncode is like dataurl.Encode(), except it only returns a data URL, and
+// panic()s if there was an error.
+func MustEncode(mediaType string, data []byte, encoding Encoding) string {
+	dataURL, err := Encode(mediaType, data, encoding)
+	if nil != err {
+		panic(err)
+	}
+
+	return dataURL
+}
+
+
+// GuessMediaType tries to figure out the media type of 'data', using the (file) name
+// given in parameter 'name' (with dataurl.MediaTypeForFilename()) and, if that does not
+// work, by sniffing the contents of 'data'.
+//
+// 'name' may be the empty string.
+//
+// If the media type is a "text/*" type, and 'data' is valid UTF-8, then the
+// "charset=utf-8" parameter is included.
+//
+// If nothing better can be figured out, then GuessMediaType returns "application/octet-stream".
+func GuessMediaType(name string, data []byte) string {
+	if mediaType := MediaTypeForFilename(name); "" != mediaType {
+		if strings.HasPrefix(mediaType, "text/") && utf8.Valid(data) {
+			mediaType += ";charset=utf-8"
+		}
+		return mediaType
+	}
+
+	return http.DetectContentType(data)
+}
+
+
+// percentEncode writes 'data' into 'buffer', percent encoding every byte that could
+// not be put into a data URL as is.
+//
+// Note that '+' is always percent encoded, since dataurl.Parse() (like most
+// decoders "in the wild") turns an unencoded '+' into a space.
+func percentEncode(buffer *bytes.Buffer, data []byte) {
+	const hex = "0123456789ABCDEF"
+
+	for _, b := range data {
+		if shouldPercentEncode(b) {
+			buffer.WriteByte('%')
+			buffer.WriteByte(hex[b>>4])
+			buffer.WriteByte(hex[b&0x0F])
+			continue
+		}
+
+		buffer.WriteByte(b)
+	}
+}
+
+
+func shouldPercentEncode(b byte) bool {
+	switch {
+	case 'A' <= b && b <= 'Z':
+		return false
+	case 'a' <= b && b <= 'z':
+		return false
+	case '0' <= b && b <= '9':
+		return false
+	}
+
+	switch b {
+	case '-', '_', '.', '~', '!', '$', '\'', '(', ')', '*', ',', ';', ':', '@', '/', '?', '=', '&':
+		return false
+	default:
+		return true
+	}
+}
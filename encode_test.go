@@ -0,0 +1,193 @@
+package dataurl
+
+
+import (
+	"strings"
+	"testing"
+)
+
+
+func TestEncode(t *testing.T) {
+
+	tests := []struct{
+		MediaType string
+		Data      string
+		Encoding  Encoding
+		Expected  string
+	}{
+		{
+			MediaType: "",
+			Data:      "",
+			Encoding:  EncodingPercent,
+			Expected:  `data:,`,
+		},
+		{
+			MediaType: "",
+			Data:      "",
+			Encoding:  EncodingBase64,
+			Expected:  `data:;base64,`,
+		},
+		{
+			MediaType: "",
+			Data:      "A brief note",
+			Encoding:  EncodingPercent,
+			Expected:  `data:,A%20brief%20note`,
+		},
+		{
+			MediaType: "text/plain;charset=utf-8",
+			Data:      "This is a test!",
+			Encoding:  EncodingBase64,
+			Expected:  `data:text/plain;charset=utf-8;base64,VGhpcyBpcyBhIHRlc3Qh`,
+		},
+		{
+			MediaType: "TEXT/Plain; Charset=utf-8",
+			Data:      "This is a test!",
+			Encoding:  EncodingPercent,
+			Expected:  `data:text/plain;charset=utf-8,This%20is%20a%20test!`,
+		},
+		{
+			MediaType: ";charset=utf-8",
+			Data:      "1+1=2",
+			Encoding:  EncodingPercent,
+			Expected:  `data:text/plain;charset=utf-8,1%2B1=2`,
+		},
+		{
+			MediaType: "text/csv",
+			Data:      "a,b\r\nc,d",
+			Encoding:  EncodingPercent,
+			Expected:  `data:text/csv,a,b%0D%0Ac,d`,
+		},
+		{
+			MediaType: "application/octet-stream",
+			Data:      "\x00\x01\xfe\xff",
+			Encoding:  EncodingBase64,
+			Expected:  `data:application/octet-stream;base64,AAH+/w==`,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		actual, err := Encode(test.MediaType, []byte(test.Data), test.Encoding)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if expected := test.Expected; expected != actual {
+			t.Errorf("For test #%d, expected data URL to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+
+		parcel, err := Parse(actual)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error when parsing the encoded data URL, but actually got one: %v\nData URL: %q", testNumber, err, actual)
+			continue
+		}
+
+		if expected, actual := test.Data, parcel.String(); expected != actual {
+			t.Errorf("For test #%d, expected the parsed content to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+	}
+}
+
+
+func TestEncodeFail(t *testing.T) {
+
+	tests := []struct{
+		MediaType string
+	}{
+		{
+			MediaType: "apple/banana/cherry",
+		},
+		{
+			MediaType: "apple//banana",
+		},
+		{
+			MediaType: "apple/banana;;cherry=grape",
+		},
+		{
+			MediaType: `text/plain;name="a,b"`,
+		},
+		{
+			MediaType: `text/plain;name="a b"`,
+		},
+		{
+			MediaType: `text/plain;name="a\"b"`,
+		},
+		{
+			MediaType: "text/plain;name=\"a\tb\"",
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		_, err := Encode(test.MediaType, []byte("Hello"), EncodingBase64)
+		if nil == err {
+			t.Errorf("For test #%d, expected an error, but actually did not get one.\nMedia Type: %q", testNumber, test.MediaType)
+			continue
+		}
+		if _, ok := err.(BadMediaTypeComplainer); !ok {
+			t.Errorf("For test #%d, expected the error to be a BadMediaTypeComplainer, but actually was %T: %v", testNumber, err, err)
+			continue
+		}
+	}
+}
+
+
+// Percent encoded contents can have a ";base64," (or a ",") in them; as a data URL of CSS, that itself
+// has a data URL in it, does. Parse() must still find where the media type ends.
+func TestEncodeThenParse(t *testing.T) {
+
+	tests := []struct{
+		MediaType string
+		Data      string
+	}{
+		{
+			MediaType: "text/css",
+			Data:      `a{background:url(data:image/png;base64,iVBORw0KGgo=)}`,
+		},
+		{
+			MediaType: "text/plain;charset=utf-8",
+			Data:      `a,b;base64,c`,
+		},
+		{
+			MediaType: "text/html",
+			Data:      `<a href="data:;base64,SGk=">hi</a>`,
+		},
+		{
+			MediaType: `text/plain;name="a/b.txt"`,
+			Data:      `hi`,
+		},
+		{
+			MediaType: `image/png;name=logo.png`,
+			Data:      `hi`,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		for _, encoding := range []Encoding{EncodingPercent, EncodingBase64} {
+			dataURL, err := Encode(test.MediaType, []byte(test.Data), encoding)
+			if nil != err {
+				t.Errorf("For test #%d and encoding %v, did not expect an error, but actually got one: (%T) %q", testNumber, encoding, err, err)
+				continue
+			}
+
+			parcel, err := Parse(dataURL)
+			if nil != err {
+				t.Errorf("For test #%d and encoding %v, did not expect an error, but actually got one: (%T) %q\nData URL: %q", testNumber, encoding, err, err, dataURL)
+				continue
+			}
+
+			if expected, actual := test.Data, parcel.String(); expected != actual {
+				t.Errorf("For test #%d and encoding %v, expected %q, but actually got %q.\nData URL: %q", testNumber, encoding, expected, actual, dataURL)
+				continue
+			}
+			if expected, actual := test.MediaType, parcel.MediaType(); !strings.HasPrefix(actual, expected) {
+				t.Errorf("For test #%d and encoding %v, expected media type to start with %q, but actually was %q.\nData URL: %q", testNumber, encoding, expected, actual, dataURL)
+				continue
+			}
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package dataurl
+
+
+import (
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+	"sync"
+)
+
+
+// builtinExtensions is the built-in table used by ExtensionFor() and MediaTypeForFilename().
+//
+// For each media type, the 1st file extension is the one ExtensionFor() returns.
+//
+// Unlike mime.ExtensionsByType() and mime.TypeByExtension(), this does not depend on
+// what (if any) "mime.types" file is on the system. So the results are the same on
+// every machine.
+var builtinExtensions = []struct{
+	MediaType  string
+	Extensions []string
+}{
+	// Web
+	{"text/html",                     []string{".html", ".htm"}},
+	{"text/css",                      []string{".css"}},
+	{"text/javascript",               []string{".js", ".mjs"}},
+	{"application/json",              []string{".json"}},
+	{"application/ld+json",           []string{".jsonld"}},
+	{"application/manifest+json",     []string{".webmanifest"}},
+	{"application/xml",               []string{".xml"}},
+	{"application/xhtml+xml",         []string{".xhtml"}},
+	{"application/wasm",              []string{".wasm"}},
+	{"text/plain",                    []string{".txt", ".text"}},
+	{"text/csv",                      []string{".csv"}},
+	{"text/markdown",                 []string{".md", ".markdown"}},
+	{"text/calendar",                 []string{".ics"}},
+	{"text/vtt",                      []string{".vtt"}},
+
+	// Images
+	{"image/png",                     []string{".png"}},
+	{"image/apng",                    []string{".apng"}},
+	{"image/jpeg",                    []string{".jpg", ".jpeg", ".jpe"}},
+	{"image/gif",                     []string{".gif"}},
+	{"image/webp",                    []string{".webp"}},
+	{"image/avif",                    []string{".avif"}},
+	{"image/svg+xml",                 []string{".svg"}},
+	{"image/bmp",                     []string{".bmp"}},
+	{"image/x-icon",                  []string{".ico"}},
+	{"image/tiff",                    []string{".tiff", ".tif"}},
+	{"image/heic",                    []string{".heic"}},
+
+	// Fonts
+	{"font/woff",                     []string{".woff"}},
+	{"font/woff2",                    []string{".woff2"}},
+	{"font/ttf",                      []string{".ttf"}},
+	{"font/otf",                      []string{".otf"}},
+	{"font/collection",               []string{".ttc"}},
+	{"application/vnd.ms-fontobject", []string{".eot"}},
+
+	// Audio
+	{"audio/mpeg",                    []string{".mp3"}},
+	{"audio/ogg",                     []string{".ogg", ".oga", ".opus"}},
+	{"audio/wav",                     []string{".wav"}},
+	{"audio/webm",                    []string{".weba"}},
+	{"audio/aac",                     []string{".aac"}},
+	{"audio/flac",                    []string{".flac"}},
+	{"audio/mp4",                     []string{".m4a"}},
+	{"audio/midi",                    []string{".mid", ".midi"}},
+
+	// Video
+	{"video/mp4",                     []string{".mp4", ".m4v"}},
+	{"video/webm",                    []string{".webm"}},
+	{"video/ogg",                     []string{".ogv"}},
+	{"video/quicktime",               []string{".mov"}},
+	{"video/x-msvideo",               []string{".avi"}},
+	{"video/mpeg",                    []string{".mpeg", ".mpg"}},
+
+	// Documents
+	{"application/pdf",               []string{".pdf"}},
+	{"application/rtf",               []string{".rtf"}},
+	{"application/epub+zip",          []string{".epub"}},
+	{"application/msword",            []string{".doc"}},
+	{"application/vnd.openxmlformats-officedocument.wordprocessingml.document",   []string{".docx"}},
+	{"application/vnd.ms-excel",      []string{".xls"}},
+	{"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",         []string{".xlsx"}},
+	{"application/vnd.ms-powerpoint", []string{".ppt"}},
+	{"application/vnd.openxmlformats-officedocument.presentationml.presentation", []string{".pptx"}},
+	{"application/vnd.oasis.opendocument.text",         []string{".odt"}},
+	{"application/vnd.oasis.opendocument.spreadsheet",  []string{".ods"}},
+	{"application/vnd.oasis.opendocument.presentation", []string{".odp"}},
+
+	// Archives & Other
+	{"application/zip",               []string{".zip"}},
+	{"application/gzip",              []string{".gz"}},
+	{"application/x-tar",             []string{".tar"}},
+	{"application/octet-stream",      []string{".bin"}},
+}
+
+
+// mediaTypeAliases maps media types that are seen "in the wild" (but are not
+// the registered name) to the registered name.
+//
+// For example: "image/jpg" → "image/jpeg".
+var mediaTypeAliases = map[string]string{
+	"application/font-woff":    "font/woff",
+	"application/javascript":   "text/javascript",
+	"application/x-font-otf":   "font/otf",
+	"application/x-font-ttf":   "font/ttf",
+	"application/x-gzip":       "application/gzip",
+	"application/x-javascript": "text/javascript",
+	"audio/mp3":                "audio/mpeg",
+	"audio/wave":               "audio/wav",
+	"audio/x-wav":              "audio/wav",
+	"image/jpg":                "image/jpeg",
+	"image/pjpeg":              "image/jpeg",
+	"image/vnd.microsoft.icon": "image/x-icon",
+	"image/x-ms-bmp":           "image/bmp",
+	"image/x-png":              "image/png",
+	"text/x-markdown":          "text/markdown",
+}
+
+
+var extensionRegistry struct {
+	mutex         sync.RWMutex
+	extensionFor  map[string]string
+	mediaTypeFor  map[string]string
+}
+
+
+func init() {
+	extensionRegistry.extensionFor = map[string]string{}
+	extensionRegistry.mediaTypeFor = map[string]string{}
+
+	for _, entry := range builtinExtensions {
+		extensionRegistry.extensionFor[entry.MediaType] = entry.Extensions[0]
+
+		for _, extension := range entry.Extensions {
+			extensionRegistry.mediaTypeFor[extension] = entry.MediaType
+		}
+	}
+}
+
+
+// ExtensionFor returns the file extension (including the leading ".") to use for
+// content with the media type 'mediaType'.
+//
+// Any parameters in 'mediaType' are ignored. So, for example, both "image/png" and
+// "image/png;charset=US-ASCII" result in ".png". (Which means that the result of
+// Parcel.MediaType() can be passed as is.)
+//
+// If the media type is unknown, then ExtensionFor returns the empty string.
+//
+// Example usage:
+//
+//	parcel := dataurl.MustParse("data:image/png;base64,iVBORw0KGgo=")
+//
+//	extension := dataurl.ExtensionFor(parcel.MediaType()) // extension == ".png"
+func ExtensionFor(mediaType string) string {
+	mimeType := essenceOf(mediaType)
+
+	if alias, ok := mediaTypeAliases[mimeType]; ok {
+		mimeType = alias
+	}
+
+	extensionRegistry.mutex.RLock()
+	defer extensionRegistry.mutex.RUnlock()
+
+	return extensionRegistry.extensionFor[mimeType]
+}
+
+
+// MediaTypeForFilename returns the media type for a file with the (file) name 'name',
+// based on its file extension.
+//
+// The file extension is matched case-insensitively.
+//
+// If the file extension is unknown, then MediaTypeForFilename returns the empty string.
+//
+// Example usage:
+//
+//	mediaType := dataurl.MediaTypeForFilename("images/Logo.PNG") // mediaType == "image/png"
+func MediaTypeForFilename(name string) string {
+	extension := strings.ToLower(path.Ext(strings.ReplaceAll(name, `\`, "/")))
+	if "" == extension {
+		return ""
+	}
+
+	extensionRegistry.mutex.RLock()
+	defer extensionRegistry.mutex.RUnlock()
+
+	return extensionRegistry.mediaTypeFor[extension]
+}
+
+
+// RegisterExtension adds (or replaces) the file extensions used for the media type
+// 'mediaType'.
+//
+// The 1st extension becomes the one ExtensionFor() returns for 'mediaType'. And every
+// extension given maps back to 'mediaType' with MediaTypeForFilename(). Extensions that were
+// registered for 'mediaType' before, but are not given, no longer map back to it.
+//
+// If 'mediaType' is an alias (ex: "image/jpg"), then the extensions are registered for the
+// media type it is an alias of (ex: "image/jpeg"); since that is what ExtensionFor() looks up.
+//
+// The leading "." on each extension is optional.
+//
+// Example usage:
+//
+//	err := dataurl.RegisterExtension("application/x-apple-banana-cherry", ".abc")
+//	if nil != err {
+//		//@TODO
+//	}
+//
+// RegisterExtension is safe to call from multiple goroutines.
+func RegisterExtension(mediaType string, extensions ...string) error {
+	mimeType, _, err := mime.ParseMediaType(mediaType)
+	if nil != err {
+		return newBadMediaTypeComplainer(err)
+	}
+
+	if alias, ok := mediaTypeAliases[mimeType]; ok {
+		mimeType = alias
+	}
+
+	if len(extensions) < 1 {
+		return newBadMediaTypeComplainer(fmt.Errorf("no file extensions given for media type %q", mimeType))
+	}
+
+	var normalized []string
+	for _, extension := range extensions {
+		extension = strings.ToLower(extension)
+		if !strings.HasPrefix(extension, ".") {
+			extension = "." + extension
+		}
+		if "." == extension || strings.ContainsAny(extension, `/\`) {
+			return newBadMediaTypeComplainer(fmt.Errorf("bad file extension %q for media type %q", extension, mimeType))
+		}
+
+		normalized = append(normalized, extension)
+	}
+
+	extensionRegistry.mutex.Lock()
+	defer extensionRegistry.mutex.Unlock()
+
+	// Replacing the extensions for a media type removes its old ones.
+	for extension, registered := range extensionRegistry.mediaTypeFor {
+		if registered == mimeType {
+			delete(extensionRegistry.mediaTypeFor, extension)
+		}
+	}
+
+	extensionRegistry.extensionFor[mimeType] = normalized[0]
+	for _, extension := range normalized {
+		extensionRegistry.mediaTypeFor[extension] = mimeType
+	}
+
+	return nil
+}
+
+
+// essenceOf returns the media type 'mediaType' without any parameters, lower-cased.
+//
+// For example: "Image/PNG;charset=US-ASCII" → "image/png".
+func essenceOf(mediaType string) string {
+	if index := strings.IndexByte(mediaType, ';'); -1 != index {
+		mediaType = mediaType[:index]
+	}
+
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
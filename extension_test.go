@@ -0,0 +1,173 @@
+package dataurl
+
+
+import (
+	"testing"
+)
+
+
+func TestExtensionFor(t *testing.T) {
+
+	tests := []struct{
+		MediaType string
+		Expected  string
+	}{
+		{
+			MediaType: "image/png",
+			Expected:  ".png",
+		},
+		{
+			MediaType: "image/png;charset=US-ASCII",
+			Expected:  ".png",
+		},
+		{
+			MediaType: "IMAGE/JPEG",
+			Expected:  ".jpg",
+		},
+		{
+			MediaType: "image/jpg",
+			Expected:  ".jpg",
+		},
+		{
+			MediaType: "text/plain;charset=US-ASCII",
+			Expected:  ".txt",
+		},
+		{
+			MediaType: "application/javascript",
+			Expected:  ".js",
+		},
+		{
+			MediaType: "font/woff2",
+			Expected:  ".woff2",
+		},
+		{
+			MediaType: "application/x-apple-banana-cherry",
+			Expected:  "",
+		},
+		{
+			MediaType: "",
+			Expected:  "",
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		if expected, actual := test.Expected, ExtensionFor(test.MediaType); expected != actual {
+			t.Errorf("For test #%d, expected extension to be %q, but actually was %q.\nMedia Type: %q", testNumber, expected, actual, test.MediaType)
+			continue
+		}
+	}
+}
+
+
+func TestMediaTypeForFilename(t *testing.T) {
+
+	tests := []struct{
+		Name     string
+		Expected string
+	}{
+		{
+			Name:     "logo.png",
+			Expected: "image/png",
+		},
+		{
+			Name:     "images/Logo.PNG",
+			Expected: "image/png",
+		},
+		{
+			Name:     `C:\images\photo.jpeg`,
+			Expected: "image/jpeg",
+		},
+		{
+			Name:     "index.html",
+			Expected: "text/html",
+		},
+		{
+			Name:     "font.woff2",
+			Expected: "font/woff2",
+		},
+		{
+			Name:     "README",
+			Expected: "",
+		},
+		{
+			Name:     "archive.apple-banana-cherry",
+			Expected: "",
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		if expected, actual := test.Expected, MediaTypeForFilename(test.Name); expected != actual {
+			t.Errorf("For test #%d, expected media type to be %q, but actually was %q.\nName: %q", testNumber, expected, actual, test.Name)
+			continue
+		}
+	}
+}
+
+
+func TestRegisterExtension(t *testing.T) {
+	if err := RegisterExtension("application/x-test-register-extension", "TRE", ".tre2"); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if expected, actual := ".tre", ExtensionFor("application/x-test-register-extension"); expected != actual {
+		t.Errorf("Expected extension to be %q, but actually was %q.", expected, actual)
+	}
+	if expected, actual := "application/x-test-register-extension", MediaTypeForFilename("file.tre2"); expected != actual {
+		t.Errorf("Expected media type to be %q, but actually was %q.", expected, actual)
+	}
+
+	if err := RegisterExtension("apple//banana", ".ab"); nil == err {
+		t.Errorf("Expected an error for a bad media type, but actually did not get one.")
+	}
+	if err := RegisterExtension("application/x-test-register-extension"); nil == err {
+		t.Errorf("Expected an error when no extensions are given, but actually did not get one.")
+	}
+}
+
+
+func TestRegisterExtensionReplaces(t *testing.T) {
+	if err := RegisterExtension("application/x-test-register-extension-replaces", ".trr1", ".trr2"); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+	if err := RegisterExtension("application/x-test-register-extension-replaces", ".trr3"); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if expected, actual := ".trr3", ExtensionFor("application/x-test-register-extension-replaces"); expected != actual {
+		t.Errorf("Expected extension to be %q, but actually was %q.", expected, actual)
+	}
+	if expected, actual := "application/x-test-register-extension-replaces", MediaTypeForFilename("file.trr3"); expected != actual {
+		t.Errorf("Expected media type to be %q, but actually was %q.", expected, actual)
+	}
+
+	for _, name := range []string{"file.trr1", "file.trr2"} {
+		if expected, actual := "", MediaTypeForFilename(name); expected != actual {
+			t.Errorf("Expected the replaced extension of %q to have no media type, but actually was %q.", name, actual)
+		}
+	}
+}
+
+
+func TestRegisterExtensionAlias(t *testing.T) {
+	// Put "image/jpeg" back the way it was (in builtinExtensions) afterwards.
+	defer RegisterExtension("image/jpeg", ".jpg", ".jpeg", ".jpe")
+
+	if err := RegisterExtension("IMAGE/JPG", ".jpeg", ".jpg"); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	for _, mediaType := range []string{"image/jpg", "image/jpeg", "image/pjpeg"} {
+		if expected, actual := ".jpeg", ExtensionFor(mediaType); expected != actual {
+			t.Errorf("For media type %q, expected extension to be %q, but actually was %q.", mediaType, expected, actual)
+		}
+	}
+
+	if expected, actual := "image/jpeg", MediaTypeForFilename("photo.jpeg"); expected != actual {
+		t.Errorf("Expected media type to be %q, but actually was %q.", expected, actual)
+	}
+	if expected, actual := "", MediaTypeForFilename("photo.jpe"); expected != actual {
+		t.Errorf("Expected media type to be %q, but actually was %q.", expected, actual)
+	}
+}
@@ -0,0 +1,535 @@
+package dataurl
+
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+
+// ImageInformation is what dataurl.ImageInfo() returns.
+type ImageInformation struct {
+	// Format is the name of the image format.
+	// One of: "bmp", "gif", "ico", "jpeg", "png", "svg" or "webp".
+	Format string
+
+	// MediaType is the media type that goes with Format.
+	// For example: "image/png".
+	MediaType string
+
+	Width  int
+	Height int
+
+	// Frames is the number of frames in an animated image (APNG, animated GIF,
+	// animated WebP), or the number of images in an ICO file. Otherwise it is 1.
+	Frames int
+
+	// Animated is true for animated images.
+	Animated bool
+
+	// ColorModel is a short description of how the pixels are stored.
+	// For example: "gray", "paletted", "RGB", "RGBA", "YCbCr", "CMYK".
+	// It is the empty string for SVG images.
+	ColorModel string
+
+	// DeclaredMediaTypeMatches is true if the media type declared by the data URL
+	// (ex: "image/jpg") is the same as (or an alias of) MediaType.
+	DeclaredMediaTypeMatches bool
+}
+
+
+var (
+	errImageTooShort = errors.New("image data ends before its header does")
+)
+
+
+// ImageInfo reads the header of the image contained in 'parcel', and returns information
+// about it; without decoding the (whole) image.
+//
+// PNG (and APNG), GIF, JPEG, WebP, BMP, ICO and SVG images are supported.
+//
+// The image format is figured out from the contents, and not the media type declared
+// by the data URL. The field DeclaredMediaTypeMatches of what is returned says whether
+// they agree.
+//
+// Example usage:
+//
+//	parcel, err := dataurl.Parse(dataURL)
+//	if nil != err {
+//		//@TODO
+//	}
+//
+//	info, err := dataurl.ImageInfo(parcel)
+//	if nil != err {
+//		//@TODO
+//	}
+//	if !info.DeclaredMediaTypeMatches {
+//		//@TODO
+//	}
+//
+//	fmt.Printf("%s image, %dx%d, %d frame(s)\n", info.Format, info.Width, info.Height, info.Frames)
+//
+// If the contents is not a supported image, then ImageInfo returns a BadMediaTypeComplainer.
+// If the header of the image is truncated or corrupt, then ImageInfo returns a SyntaxErrorComplainer.
+// If 'parcel' is nil, then ImageInfo returns a BadRequestComplainer.
+func ImageInfo(parcel Parcel) (ImageInformation, error) {
+	var info ImageInformation
+
+	if nil == parcel {
+		return info, newBadRequestComplainer("nil parcel passed to dataurl.ImageInfo().")
+	}
+
+	data := UnsafeBytes(parcel)
+
+	var err error
+	switch {
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		info, err = pngInfo(data)
+	case bytes.HasPrefix(data, []byte("GIF87a")), bytes.HasPrefix(data, []byte("GIF89a")):
+		info, err = gifInfo(data)
+	case bytes.HasPrefix(data, []byte("\xff\xd8")):
+		info, err = jpegInfo(data)
+	case 12 <= len(data) && bytes.HasPrefix(data, []byte("RIFF")) && "WEBP" == string(data[8:12]):
+		info, err = webpInfo(data)
+	case bytes.HasPrefix(data, []byte("BM")):
+		info, err = bmpInfo(data)
+	case bytes.HasPrefix(data, []byte("\x00\x00\x01\x00")), bytes.HasPrefix(data, []byte("\x00\x00\x02\x00")):
+		info, err = icoInfo(data)
+	case looksLikeXML(data):
+		info, err = svgInfo(data)
+	default:
+		return info, newBadMediaTypeComplainer(errors.New("contents is not a supported image format"))
+	}
+	if nil != err {
+		if _, ok := err.(BadMediaTypeComplainer); ok {
+			return ImageInformation{}, err
+		}
+		return ImageInformation{}, newSyntaxErrorComplainer("bad %s image header: %s", info.Format, err)
+	}
+
+	declared := essenceOf(parcel.MediaType())
+	if alias, ok := mediaTypeAliases[declared]; ok {
+		declared = alias
+	}
+	info.DeclaredMediaTypeMatches = declared == info.MediaType
+
+	return info, nil
+}
+
+
+func pngInfo(data []byte) (ImageInformation, error) {
+	info := ImageInformation{
+		Format:    "png",
+		MediaType: "image/png",
+		Frames:    1,
+	}
+
+	// The IHDR chunk must be first.
+	if len(data) < 8+8+13 || "IHDR" != string(data[12:16]) {
+		return info, errImageTooShort
+	}
+
+	info.Width  = int(binary.BigEndian.Uint32(data[16:20]))
+	info.Height = int(binary.BigEndian.Uint32(data[20:24]))
+
+	switch data[25] {
+	case 0:
+		info.ColorModel = "gray"
+	case 2:
+		info.ColorModel = "RGB"
+	case 3:
+		info.ColorModel = "paletted"
+	case 4:
+		info.ColorModel = "gray+alpha"
+	case 6:
+		info.ColorModel = "RGBA"
+	default:
+		return info, fmt.Errorf("unknown PNG color type %d", data[25])
+	}
+
+	// An APNG has an acTL chunk before the first IDAT chunk.
+	for offset := 8; offset+8 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[offset:offset+4]))
+		chunkType := string(data[offset+4:offset+8])
+
+		if "IDAT" == chunkType {
+			break
+		}
+		if "acTL" == chunkType && offset+12 <= len(data) {
+			info.Frames = int(binary.BigEndian.Uint32(data[offset+8:offset+12]))
+			info.Animated = true
+			break
+		}
+
+		offset += 12 + length // length + type + data + CRC
+		if length < 0 || offset < 0 {
+			break
+		}
+	}
+
+	return info, nil
+}
+
+
+func gifInfo(data []byte) (ImageInformation, error) {
+	info := ImageInformation{
+		Format:     "gif",
+		MediaType:  "image/gif",
+		ColorModel: "paletted",
+	}
+
+	if len(data) < 13 {
+		return info, errImageTooShort
+	}
+
+	info.Width  = int(binary.LittleEndian.Uint16(data[6:8]))
+	info.Height = int(binary.LittleEndian.Uint16(data[8:10]))
+
+	offset := 13
+	if flags := data[10]; 0 != flags&0x80 {
+		offset += 3 << ((flags & 0x07) + 1)
+	}
+
+	// skipSubBlocks returns the offset just past a sequence of data sub-blocks.
+	skipSubBlocks := func(offset int) int {
+		for offset < len(data) {
+			size := int(data[offset])
+			offset += 1 + size
+			if 0 == size {
+				break
+			}
+		}
+		return offset
+	}
+
+	// Count the image descriptors.
+loop:
+	for offset < len(data) {
+		switch data[offset] {
+		case 0x2C: // Image Descriptor
+			if len(data) < offset+10 {
+				break loop
+			}
+			info.Frames++
+
+			flags := data[offset+9]
+			offset += 10
+			if 0 != flags&0x80 {
+				offset += 3 << ((flags & 0x07) + 1)
+			}
+			offset++ // LZW minimum code size
+			offset = skipSubBlocks(offset)
+		case 0x21: // Extension
+			offset = skipSubBlocks(offset + 2)
+		case 0x3B: // Trailer
+			break loop
+		default:
+			break loop
+		}
+	}
+
+	if info.Frames < 1 {
+		info.Frames = 1
+	}
+	info.Animated = 1 < info.Frames
+
+	return info, nil
+}
+
+
+func jpegInfo(data []byte) (ImageInformation, error) {
+	info := ImageInformation{
+		Format:    "jpeg",
+		MediaType: "image/jpeg",
+		Frames:    1,
+	}
+
+	offset := 2
+	for offset+4 <= len(data) {
+		if 0xFF != data[offset] {
+			return info, fmt.Errorf("expected a JPEG marker at byte %d", offset)
+		}
+
+		marker := data[offset+1]
+		switch {
+		case 0xFF == marker: // Fill byte.
+			offset++
+			continue
+		case 0x01 == marker, 0xD0 <= marker && marker <= 0xD7: // Markers without a length.
+			offset += 2
+			continue
+		case 0xD9 == marker || 0xDA == marker: // EOI or SOS, before any SOF.
+			return info, errImageTooShort
+		}
+
+		length := int(binary.BigEndian.Uint16(data[offset+2:offset+4]))
+
+		isSOF := 0xC0 <= marker && marker <= 0xCF && 0xC4 != marker && 0xC8 != marker && 0xCC != marker
+		if isSOF {
+			if len(data) < offset+10 {
+				return info, errImageTooShort
+			}
+
+			info.Height = int(binary.BigEndian.Uint16(data[offset+5:offset+7]))
+			info.Width  = int(binary.BigEndian.Uint16(data[offset+7:offset+9]))
+
+			switch data[offset+9] {
+			case 1:
+				info.ColorModel = "gray"
+			case 3:
+				info.ColorModel = "YCbCr"
+			case 4:
+				info.ColorModel = "CMYK"
+			default:
+				info.ColorModel = fmt.Sprintf("%d components", data[offset+9])
+			}
+
+			return info, nil
+		}
+
+		offset += 2 + length
+	}
+
+	return info, errImageTooShort
+}
+
+
+func webpInfo(data []byte) (ImageInformation, error) {
+	info := ImageInformation{
+		Format:    "webp",
+		MediaType: "image/webp",
+		Frames:    1,
+	}
+
+	if len(data) < 20 {
+		return info, errImageTooShort
+	}
+
+	chunk := data[20:]
+	switch string(data[12:16]) {
+	case "VP8 ":
+		if len(chunk) < 10 || "\x9d\x01\x2a" != string(chunk[3:6]) {
+			return info, errImageTooShort
+		}
+		info.Width  = int(binary.LittleEndian.Uint16(chunk[6:8]) & 0x3FFF)
+		info.Height = int(binary.LittleEndian.Uint16(chunk[8:10]) & 0x3FFF)
+		info.ColorModel = "YCbCr"
+	case "VP8L":
+		if len(chunk) < 5 || 0x2F != chunk[0] {
+			return info, errImageTooShort
+		}
+		bits := binary.LittleEndian.Uint32(chunk[1:5])
+		info.Width  = int(bits&0x3FFF) + 1
+		info.Height = int((bits>>14)&0x3FFF) + 1
+		info.ColorModel = "RGB"
+		if 0 != (bits>>28)&1 {
+			info.ColorModel = "RGBA"
+		}
+	case "VP8X":
+		if len(chunk) < 10 {
+			return info, errImageTooShort
+		}
+		flags := chunk[0]
+		info.Width  = int(uint32(chunk[4]) | uint32(chunk[5])<<8 | uint32(chunk[6])<<16) + 1
+		info.Height = int(uint32(chunk[7]) | uint32(chunk[8])<<8 | uint32(chunk[9])<<16) + 1
+		info.ColorModel = "RGB"
+		if 0 != flags&0x10 {
+			info.ColorModel = "RGBA"
+		}
+
+		if 0 != flags&0x02 {
+			info.Animated = true
+			info.Frames = 0
+
+			// Count the ANMF chunks.
+			for offset := 12; offset+8 <= len(data); {
+				size := int(binary.LittleEndian.Uint32(data[offset+4:offset+8]))
+				if "ANMF" == string(data[offset:offset+4]) {
+					info.Frames++
+				}
+				offset += 8 + size + size&1
+				if size < 0 || offset < 0 {
+					break
+				}
+			}
+		}
+	default:
+		return info, fmt.Errorf("unknown WebP chunk %q", data[12:16])
+	}
+
+	return info, nil
+}
+
+
+func bmpInfo(data []byte) (ImageInformation, error) {
+	info := ImageInformation{
+		Format:    "bmp",
+		MediaType: "image/bmp",
+		Frames:    1,
+	}
+
+	if len(data) < 18 {
+		return info, errImageTooShort
+	}
+
+	var bitsPerPixel int
+	switch headerSize := binary.LittleEndian.Uint32(data[14:18]); {
+	case 12 == headerSize: // BITMAPCOREHEADER
+		if len(data) < 26 {
+			return info, errImageTooShort
+		}
+		info.Width   = int(binary.LittleEndian.Uint16(data[18:20]))
+		info.Height  = int(binary.LittleEndian.Uint16(data[20:22]))
+		bitsPerPixel = int(binary.LittleEndian.Uint16(data[24:26]))
+	case 40 <= headerSize:
+		if len(data) < 30 {
+			return info, errImageTooShort
+		}
+		info.Width   = int(int32(binary.LittleEndian.Uint32(data[18:22])))
+		info.Height  = int(int32(binary.LittleEndian.Uint32(data[22:26])))
+		bitsPerPixel = int(binary.LittleEndian.Uint16(data[28:30]))
+	default:
+		return info, fmt.Errorf("unknown BMP header size %d", headerSize)
+	}
+
+	// A negative height means the rows are stored top-down.
+	if info.Height < 0 {
+		info.Height = -info.Height
+	}
+
+	switch {
+	case bitsPerPixel <= 8:
+		info.ColorModel = "paletted"
+	case 32 == bitsPerPixel:
+		info.ColorModel = "RGBA"
+	default:
+		info.ColorModel = "RGB"
+	}
+
+	return info, nil
+}
+
+
+func icoInfo(data []byte) (ImageInformation, error) {
+	info := ImageInformation{
+		Format:    "ico",
+		MediaType: "image/x-icon",
+	}
+
+	if len(data) < 6 {
+		return info, errImageTooShort
+	}
+
+	count := int(binary.LittleEndian.Uint16(data[4:6]))
+	if count < 1 || len(data) < 6+16*count {
+		return info, errImageTooShort
+	}
+	info.Frames = count
+
+	// Report the largest of the images.
+	for i := 0; i < count; i++ {
+		entry := data[6+16*i:]
+
+		width, height := int(entry[0]), int(entry[1])
+		if 0 == width {
+			width = 256
+		}
+		if 0 == height {
+			height = 256
+		}
+
+		if info.Width*info.Height < width*height {
+			info.Width, info.Height = width, height
+
+			switch bitsPerPixel := binary.LittleEndian.Uint16(entry[6:8]); {
+			case 32 == bitsPerPixel:
+				info.ColorModel = "RGBA"
+			case 0 < bitsPerPixel && bitsPerPixel <= 8:
+				info.ColorModel = "paletted"
+			default:
+				info.ColorModel = "RGB"
+			}
+		}
+	}
+
+	return info, nil
+}
+
+
+func looksLikeXML(data []byte) bool {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	data = bytes.TrimLeft(data, " \t\r\n")
+	return bytes.HasPrefix(data, []byte("<"))
+}
+
+
+func svgInfo(data []byte) (ImageInformation, error) {
+	info := ImageInformation{
+		Format:    "svg",
+		MediaType: "image/svg+xml",
+		Frames:    1,
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	for {
+		token, err := decoder.Token()
+		if nil != err {
+			return info, newBadMediaTypeComplainer(errors.New("contents is not a supported image format"))
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if "svg" != element.Name.Local {
+			return info, newBadMediaTypeComplainer(fmt.Errorf("contents is XML with a <%s> root element, rather than an SVG image", element.Name.Local))
+		}
+
+		var width, height, viewBox string
+		for _, attr := range element.Attr {
+			switch attr.Name.Local {
+			case "width":
+				width = attr.Value
+			case "height":
+				height = attr.Value
+			case "viewBox":
+				viewBox = attr.Value
+			}
+		}
+
+		var viewBoxWidth, viewBoxHeight float64
+		if fields := strings.FieldsFunc(viewBox, func(r rune) bool { return ' ' == r || ',' == r || '\t' == r || '\n' == r }); 4 == len(fields) {
+			viewBoxWidth, _  = strconv.ParseFloat(fields[2], 64)
+			viewBoxHeight, _ = strconv.ParseFloat(fields[3], 64)
+		}
+
+		info.Width  = svgLength(width, viewBoxWidth)
+		info.Height = svgLength(height, viewBoxHeight)
+
+		return info, nil
+	}
+}
+
+
+// svgLength turns the value of a width or height attribute of an <svg> element into
+// a number of pixels. If it is missing, or relative (ex: "100%"), then the length from
+// the viewBox is used.
+func svgLength(value string, viewBoxLength float64) int {
+	value = strings.TrimSpace(value)
+	value = strings.TrimSuffix(value, "px")
+
+	length, err := strconv.ParseFloat(value, 64)
+	if nil != err || length <= 0 {
+		length = viewBoxLength
+	}
+
+	return int(math.Round(length))
+}
@@ -0,0 +1,165 @@
+package dataurl
+
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+
+func TestImageInfo(t *testing.T) {
+
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, image.NewNRGBA(image.Rect(0, 0, 17, 9))); nil != err {
+		t.Fatalf("Could not create PNG: %v", err)
+	}
+
+	var jpegData bytes.Buffer
+	if err := jpeg.Encode(&jpegData, image.NewRGBA(image.Rect(0, 0, 31, 7)), nil); nil != err {
+		t.Fatalf("Could not create JPEG: %v", err)
+	}
+
+	var gifData bytes.Buffer
+	{
+		frame := image.NewPaletted(image.Rect(0, 0, 5, 4), palette.Plan9)
+		frame.Set(1, 1, color.White)
+
+		animation := gif.GIF{
+			Image: []*image.Paletted{frame, frame, frame},
+			Delay: []int{10, 10, 10},
+		}
+		if err := gif.EncodeAll(&gifData, &animation); nil != err {
+			t.Fatalf("Could not create GIF: %v", err)
+		}
+	}
+
+	tests := []struct{
+		MediaType       string
+		Data            []byte
+		Expected        ImageInformation
+	}{
+		{
+			MediaType: "image/png",
+			Data:      pngData.Bytes(),
+			Expected:  ImageInformation{Format:"png", MediaType:"image/png", Width:17, Height:9, Frames:1, ColorModel:"RGBA", DeclaredMediaTypeMatches:true},
+		},
+		{
+			MediaType: "image/jpeg",
+			Data:      pngData.Bytes(),
+			Expected:  ImageInformation{Format:"png", MediaType:"image/png", Width:17, Height:9, Frames:1, ColorModel:"RGBA", DeclaredMediaTypeMatches:false},
+		},
+		{
+			MediaType: "image/jpg",
+			Data:      jpegData.Bytes(),
+			Expected:  ImageInformation{Format:"jpeg", MediaType:"image/jpeg", Width:31, Height:7, Frames:1, ColorModel:"YCbCr", DeclaredMediaTypeMatches:true},
+		},
+		{
+			MediaType: "image/gif",
+			Data:      gifData.Bytes(),
+			Expected:  ImageInformation{Format:"gif", MediaType:"image/gif", Width:5, Height:4, Frames:3, Animated:true, ColorModel:"paletted", DeclaredMediaTypeMatches:true},
+		},
+		{
+			MediaType: "image/webp",
+			// A 2x3 lossless WebP (header only).
+			Data:      []byte("RIFF\x1a\x00\x00\x00WEBPVP8L\x0d\x00\x00\x00\x2f\x01\x80\x00\x00"),
+			Expected:  ImageInformation{Format:"webp", MediaType:"image/webp", Width:2, Height:3, Frames:1, ColorModel:"RGB", DeclaredMediaTypeMatches:true},
+		},
+		{
+			MediaType: "image/webp",
+			// An animated 100x50 WebP with 2 frames (headers only).
+			Data:      []byte("RIFF\x00\x00\x00\x00WEBPVP8X\x0a\x00\x00\x00\x12\x00\x00\x00\x63\x00\x00\x31\x00\x00ANIM\x06\x00\x00\x00\x00\x00\x00\x00\x00\x00ANMF\x00\x00\x00\x00ANMF\x00\x00\x00\x00"),
+			Expected:  ImageInformation{Format:"webp", MediaType:"image/webp", Width:100, Height:50, Frames:2, Animated:true, ColorModel:"RGBA", DeclaredMediaTypeMatches:true},
+		},
+		{
+			MediaType: "image/bmp",
+			// A 4x-3 (top-down) 24-bit BMP (headers only).
+			Data:      []byte("BM\x00\x00\x00\x00\x00\x00\x00\x00\x36\x00\x00\x00\x28\x00\x00\x00\x04\x00\x00\x00\xfd\xff\xff\xff\x01\x00\x18\x00"),
+			Expected:  ImageInformation{Format:"bmp", MediaType:"image/bmp", Width:4, Height:3, Frames:1, ColorModel:"RGB", DeclaredMediaTypeMatches:true},
+		},
+		{
+			MediaType: "image/vnd.microsoft.icon",
+			// An ICO with a 16x16 and a 256x256 image (directory only).
+			Data:      []byte("\x00\x00\x01\x00\x02\x00" + "\x10\x10\x00\x00\x01\x00\x20\x00\x00\x00\x00\x00\x00\x00\x00\x00" + "\x00\x00\x00\x00\x01\x00\x20\x00\x00\x00\x00\x00\x00\x00\x00\x00"),
+			Expected:  ImageInformation{Format:"ico", MediaType:"image/x-icon", Width:256, Height:256, Frames:2, ColorModel:"RGBA", DeclaredMediaTypeMatches:true},
+		},
+		{
+			MediaType: "image/svg+xml",
+			Data:      []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg" width="24px" height="100%" viewBox="0 0 24 12"></svg>`),
+			Expected:  ImageInformation{Format:"svg", MediaType:"image/svg+xml", Width:24, Height:12, Frames:1, DeclaredMediaTypeMatches:true},
+		},
+		{
+			MediaType: "text/plain",
+			Data:      []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0,0,10.4,20"/>`),
+			Expected:  ImageInformation{Format:"svg", MediaType:"image/svg+xml", Width:10, Height:20, Frames:1, DeclaredMediaTypeMatches:false},
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		parcel := newParcel()
+		parcel.mediaType = test.MediaType
+		parcel.content = string(test.Data)
+
+		actual, err := ImageInfo(parcel)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if expected := test.Expected; expected != actual {
+			t.Errorf("For test #%d, expected %#v, but actually got %#v.", testNumber, expected, actual)
+			continue
+		}
+	}
+}
+
+
+func TestImageInfoFail(t *testing.T) {
+
+	tests := []struct{
+		Data []byte
+	}{
+		{
+			Data: []byte("Hello world!"),
+		},
+		{
+			Data: []byte(`<html><body></body></html>`),
+		},
+		{
+			Data: []byte("\x89PNG\r\n\x1a\n\x00\x00"),
+		},
+		{
+			Data: []byte("GIF89a\x01"),
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		parcel := newParcel()
+		parcel.content = string(test.Data)
+
+		_, err := ImageInfo(parcel)
+		if nil == err {
+			t.Errorf("For test #%d, expected an error, but actually did not get one.", testNumber)
+			continue
+		}
+		if _, ok := err.(BadRequestComplainer); !ok {
+			t.Errorf("For test #%d, expected the error to be a BadRequestComplainer, but actually was %T: %v", testNumber, err, err)
+			continue
+		}
+	}
+}
+
+
+func TestImageInfoNil(t *testing.T) {
+	_, err := ImageInfo(nil)
+	if _, ok := err.(BadRequestComplainer); !ok {
+		t.Errorf("Expected the error to be a BadRequestComplainer, but actually was %T: %v", err, err)
+	}
+}
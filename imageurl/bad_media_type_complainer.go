@@ -0,0 +1,43 @@
+package imageurl
+
+
+import (
+	"fmt"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+// internalBadMediaTypeComplainer is the underlying implementation, in this package, for a
+// dataurl.BadMediaTypeComplainer. It is returned for contents that are not an image that can
+// be decoded.
+type internalBadMediaTypeComplainer struct {
+	wrappedErr error
+}
+
+
+func newBadMediaTypeComplainer(err error) dataurl.BadMediaTypeComplainer {
+	complainer := internalBadMediaTypeComplainer{
+		wrappedErr:err,
+	}
+
+	return &complainer
+}
+
+
+func (complainer *internalBadMediaTypeComplainer) Error() string {
+	return fmt.Sprintf("Bad Request: Bad Media Type: imageurl: %s", complainer.wrappedErr.Error())
+}
+
+
+func (*internalBadMediaTypeComplainer) BadRequestComplainer() {
+	// Nothing here.
+}
+
+func (*internalBadMediaTypeComplainer) BadMediaTypeComplainer() {
+	// Nothing here.
+}
+
+func (complainer *internalBadMediaTypeComplainer) WrappedError() error {
+	return complainer.wrappedErr
+}
@@ -0,0 +1,42 @@
+package imageurl
+
+
+import (
+	"fmt"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+// internalBadRequestComplainer is the underlying implementation, in this package, for a
+// dataurl.BadRequestComplainer. For example, an image with too many pixels.
+type internalBadRequestComplainer struct {
+	msg string
+}
+
+
+// newBadRequestComplainer creates a new internalBadRequestComplainer (struct) and
+// returns it as a dataurl.BadRequestComplainer (interface).
+func newBadRequestComplainer(format string, a ...interface{}) dataurl.BadRequestComplainer {
+	msg := fmt.Sprintf(format, a...)
+
+	err := internalBadRequestComplainer{
+		msg:msg,
+	}
+
+	return &err
+}
+
+
+// Error method is necessary to satisfy the 'error' interface (and the BadRequestComplainer
+// interface).
+func (err *internalBadRequestComplainer) Error() string {
+	return fmt.Sprintf("Bad Request: imageurl: %s", err.msg)
+}
+
+
+// BadRequestComplainer method is necessary to satisfy the 'BadRequestComplainer' interface.
+// It exists to make this error type detectable in a Go type-switch.
+func (err *internalBadRequestComplainer) BadRequestComplainer() {
+	// Nothing here.
+}
@@ -0,0 +1,35 @@
+/*
+Package imageurl creates data URLs from images; resizing them and re-encoding them as PNG, JPEG or GIF on the way.
+
+It only uses the image codecs from the Go standard library. Since the image is always
+decoded and re-encoded, any metadata (such as EXIF or text chunks) that was in the
+original image is not in the data URL that is returned.
+
+Only PNG keeps transparency. JPEG is lossy; and GIF quantizes the image to the Plan 9
+palette. So imageurl.FormatSmallest only tries JPEG and GIF for opaque images.
+
+Example Usage
+
+	parcel, err := dataurl.Parse(avatarDataURL)
+	if nil != err {
+		//@TODO
+	}
+
+	thumbnail, err := imageurl.FromParcel(parcel, imageurl.Options{
+		MaxDimension: 64,
+		Format:       imageurl.FormatSmallest,
+		Quality:      80,
+	})
+	if nil != err {
+		//@TODO
+	}
+
+	fmt.Println(thumbnail) // data:image/png;base64,...
+
+Another Example Usage
+
+	dataURL, err := imageurl.FromImage(img, imageurl.Options{
+		Format: imageurl.FormatJPEG,
+	})
+*/
+package imageurl
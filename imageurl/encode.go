@@ -0,0 +1,134 @@
+package imageurl
+
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+// FromImage returns a data URL of 'img', resized and encoded as specified by 'options'.
+//
+// With FormatSmallest, JPEG and GIF are only tried if 'img' is opaque; since neither of them
+// would keep its transparency.
+func FromImage(img image.Image, options Options) (string, error) {
+	if nil == img {
+		return "", newBadRequestComplainer("nil image")
+	}
+
+	img = resize(img, options.MaxDimension)
+
+	formats := []Format{options.Format}
+	if FormatSmallest == options.Format {
+		formats = []Format{FormatPNG}
+		if isOpaque(img) {
+			formats = append(formats, FormatJPEG, FormatGIF)
+		}
+	}
+
+	var smallest string
+	for _, format := range formats {
+		dataURL, err := encode(img, format, options)
+		if nil != err {
+			return "", err
+		}
+
+		if "" == smallest || len(dataURL) < len(smallest) {
+			smallest = dataURL
+		}
+	}
+
+	return smallest, nil
+}
+
+
+// FromParcel decodes the image (PNG, JPEG or GIF) contained in 'parcel', and returns a data
+// URL of it, resized and re-encoded as specified by 'options'.
+//
+// Only the first frame of an animated GIF is used.
+//
+// The width and height of the image are checked before it is decoded. An image with more pixels
+// than options.MaxPixels (a decompression bomb, for example) returns a dataurl.BadRequestComplainer.
+// Contents that are not an image that can be decoded return a dataurl.BadMediaTypeComplainer.
+func FromParcel(parcel dataurl.Parcel, options Options) (string, error) {
+	if nil == parcel {
+		return "", newBadRequestComplainer("nil parcel")
+	}
+
+	data := dataurl.UnsafeBytes(parcel)
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if nil != err {
+		return "", newBadMediaTypeComplainer(fmt.Errorf("could not decode image with media type %q: %w", parcel.MediaType(), err))
+	}
+
+	if maxPixels := options.maxPixels(); 0 <= maxPixels && maxPixels < int64(config.Width) * int64(config.Height) {
+		return "", newBadRequestComplainer("image is %dx%d, which is more than %d pixels", config.Width, config.Height, maxPixels)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if nil != err {
+		return "", newBadMediaTypeComplainer(fmt.Errorf("could not decode image with media type %q: %w", parcel.MediaType(), err))
+	}
+
+	return FromImage(img, options)
+}
+
+
+// isOpaque returns whether every pixel of 'img' is fully opaque.
+func isOpaque(img image.Image) bool {
+	if opaquer, ok := img.(interface{ Opaque() bool }); ok {
+		return opaquer.Opaque()
+	}
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); 0xFFFF != a {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+
+func encode(img image.Image, format Format, options Options) (string, error) {
+	var buffer bytes.Buffer
+	var mediaType string
+
+	switch format {
+	case FormatPNG:
+		mediaType = "image/png"
+
+		encoder := png.Encoder{
+			CompressionLevel: png.BestCompression,
+		}
+		if err := encoder.Encode(&buffer, img); nil != err {
+			return "", err
+		}
+	case FormatJPEG:
+		mediaType = "image/jpeg"
+
+		if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: options.quality()}); nil != err {
+			return "", err
+		}
+	case FormatGIF:
+		mediaType = "image/gif"
+
+		if err := gif.Encode(&buffer, img, nil); nil != err {
+			return "", err
+		}
+	default:
+		return "", newBadRequestComplainer("unknown format %d", format)
+	}
+
+	return dataurl.Encode(mediaType, buffer.Bytes(), dataurl.EncodingBase64)
+}
@@ -0,0 +1,258 @@
+package imageurl
+
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+func gradient(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: uint8(x ^ y), A: 0xFF})
+		}
+	}
+	return img
+}
+
+
+func TestFromImage(t *testing.T) {
+
+	tests := []struct{
+		Image           image.Image
+		Options         Options
+		ExpectedFormat  string
+		ExpectedWidth   int
+		ExpectedHeight  int
+	}{
+		{
+			Image:          gradient(200, 100),
+			Options:        Options{MaxDimension: 50, Format: FormatPNG},
+			ExpectedFormat: "png",
+			ExpectedWidth:  50,
+			ExpectedHeight: 25,
+		},
+		{
+			Image:          gradient(100, 200),
+			Options:        Options{MaxDimension: 40, Format: FormatJPEG, Quality: 50},
+			ExpectedFormat: "jpeg",
+			ExpectedWidth:  20,
+			ExpectedHeight: 40,
+		},
+		{
+			Image:          gradient(30, 20),
+			Options:        Options{MaxDimension: 100, Format: FormatGIF},
+			ExpectedFormat: "gif",
+			ExpectedWidth:  30,
+			ExpectedHeight: 20,
+		},
+		{
+			Image:          image.NewGray(image.Rect(0, 0, 64, 64)),
+			Options:        Options{MaxDimension: 16},
+			ExpectedWidth:  16,
+			ExpectedHeight: 16,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		dataURL, err := FromImage(test.Image, test.Options)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		parcel, err := dataurl.Parse(dataURL)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error when parsing the data URL, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		info, err := dataurl.ImageInfo(parcel)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error when getting the image info, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if !info.DeclaredMediaTypeMatches {
+			t.Errorf("For test #%d, expected the declared media type to match the image, but it did not.\nMedia Type: %q", testNumber, parcel.MediaType())
+			continue
+		}
+		if expected, actual := test.ExpectedFormat, info.Format; "" != expected && expected != actual {
+			t.Errorf("For test #%d, expected format to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+		if expected, actual := test.ExpectedWidth, info.Width; expected != actual {
+			t.Errorf("For test #%d, expected width to be %d, but actually was %d.", testNumber, expected, actual)
+			continue
+		}
+		if expected, actual := test.ExpectedHeight, info.Height; expected != actual {
+			t.Errorf("For test #%d, expected height to be %d, but actually was %d.", testNumber, expected, actual)
+			continue
+		}
+	}
+}
+
+
+func TestFromImageSmallest(t *testing.T) {
+	img := gradient(64, 64)
+
+	smallest, err := FromImage(img, Options{Format: FormatSmallest})
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	for _, format := range []Format{FormatPNG, FormatJPEG, FormatGIF} {
+		dataURL, err := FromImage(img, Options{Format: format})
+		if nil != err {
+			t.Errorf("For format %s, did not expect an error, but actually got one: %v", format, err)
+			continue
+		}
+
+		if len(dataURL) < len(smallest) {
+			t.Errorf("For format %s, expected the data URL (%d bytes) to not be smaller than the smallest (%d bytes).", format, len(dataURL), len(smallest))
+			continue
+		}
+	}
+}
+
+
+func TestFromImageSmallestTransparent(t *testing.T) {
+	// Noise, so that (if it were tried) JPEG would be the smallest. The top left corner is transparent.
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	seed := uint32(1)
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			seed = seed*1664525 + 1013904223
+
+			alpha := uint8(0xFF)
+			if x < 8 && y < 8 {
+				alpha = 0
+			}
+
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x*4) ^ uint8(seed>>24)&0x1F, G: uint8(y*4), B: uint8(seed>>16), A: alpha})
+		}
+	}
+
+	for _, maxDimension := range []int{0, 32} {
+		dataURL, err := FromImage(img, Options{MaxDimension: maxDimension, Format: FormatSmallest})
+		if nil != err {
+			t.Errorf("For max dimension %d, did not expect an error, but actually got one: %v", maxDimension, err)
+			continue
+		}
+
+		parcel, err := dataurl.Parse(dataURL)
+		if nil != err {
+			t.Errorf("For max dimension %d, did not expect an error when parsing the data URL, but actually got one: %v", maxDimension, err)
+			continue
+		}
+
+		if expected, actual := "image/png", parcel.MediaType(); !strings.HasPrefix(actual, expected+";") {
+			t.Errorf("For max dimension %d, expected media type %q, but actually got %q.", maxDimension, expected, actual)
+			continue
+		}
+
+		decoded, _, err := image.Decode(parcel.Reader())
+		if nil != err {
+			t.Errorf("For max dimension %d, did not expect an error when decoding the image, but actually got one: %v", maxDimension, err)
+			continue
+		}
+
+		if _, _, _, a := decoded.At(0, 0).RGBA(); 0 != a {
+			t.Errorf("For max dimension %d, expected the top left pixel to still be transparent, but actually had alpha %d.", maxDimension, a)
+			continue
+		}
+	}
+}
+
+
+func TestFromParcel(t *testing.T) {
+	original, err := FromImage(gradient(120, 60), Options{Format: FormatPNG})
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	dataURL, err := FromParcel(dataurl.MustParse(original), Options{MaxDimension: 12, Format: FormatJPEG})
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	info, err := dataurl.ImageInfo(dataurl.MustParse(dataURL))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if expected, actual := (dataurl.ImageInformation{Format:"jpeg", MediaType:"image/jpeg", Width:12, Height:6, Frames:1, ColorModel:"YCbCr", DeclaredMediaTypeMatches:true}), info; expected != actual {
+		t.Errorf("Expected %#v, but actually got %#v.", expected, actual)
+	}
+
+	_, err = FromParcel(dataurl.MustParse("data:,Hello"), Options{})
+	if _, ok := err.(dataurl.BadMediaTypeComplainer); !ok {
+		t.Errorf("Expected a dataurl.BadMediaTypeComplainer for a parcel that is not an image, but actually got: %#v", err)
+	}
+
+	_, err = FromParcel(nil, Options{})
+	if _, ok := err.(dataurl.BadRequestComplainer); !ok {
+		t.Errorf("Expected a dataurl.BadRequestComplainer for a nil parcel, but actually got: %#v", err)
+	}
+}
+
+
+// pngHeader returns the start of a PNG, up to (and including) its IHDR chunk; which is all that
+// image.DecodeConfig() reads.
+func pngHeader(width, height uint32) []byte {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\x89PNG\r\n\x1a\n")
+
+	chunk := []byte("IHDR\x00\x00\x00\x00\x00\x00\x00\x00\x08\x06\x00\x00\x00")
+	binary.BigEndian.PutUint32(chunk[4:8], width)
+	binary.BigEndian.PutUint32(chunk[8:12], height)
+
+	binary.Write(&buffer, binary.BigEndian, uint32(len(chunk)-4))
+	buffer.Write(chunk)
+	binary.Write(&buffer, binary.BigEndian, crc32.ChecksumIEEE(chunk))
+
+	return buffer.Bytes()
+}
+
+
+func TestFromParcelMaxPixels(t *testing.T) {
+	bomb, err := dataurl.NewParcel("image/png", pngHeader(100000, 100000))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	_, err = FromParcel(bomb, Options{})
+	if _, ok := err.(dataurl.BadRequestComplainer); !ok {
+		t.Errorf("Expected a dataurl.BadRequestComplainer for an image with too many pixels, but actually got: %#v", err)
+	}
+	if _, ok := err.(dataurl.BadMediaTypeComplainer); ok {
+		t.Errorf("Did not expect a dataurl.BadMediaTypeComplainer for an image with too many pixels, but actually got one: %v", err)
+	}
+
+
+	original, err := FromImage(gradient(20, 10), Options{Format: FormatPNG})
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if _, err := FromParcel(dataurl.MustParse(original), Options{MaxPixels: 199}); nil == err {
+		t.Errorf("Expected an error for an image with more than 199 pixels, but actually did not get one.")
+	}
+	if _, err := FromParcel(dataurl.MustParse(original), Options{MaxPixels: 200}); nil != err {
+		t.Errorf("Did not expect an error for an image with 200 pixels, but actually got one: %v", err)
+	}
+	if _, err := FromParcel(dataurl.MustParse(original), Options{MaxPixels: -1}); nil != err {
+		t.Errorf("Did not expect an error with no limit, but actually got one: %v", err)
+	}
+}
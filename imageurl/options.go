@@ -0,0 +1,93 @@
+package imageurl
+
+
+// Format is used to specify which image format the data URL is encoded with.
+type Format int
+
+
+const (
+	// FormatSmallest tries each of PNG, JPEG and GIF, and uses whichever results in the shortest data URL.
+	// If the image is not opaque, then only PNG is used; since JPEG and GIF would lose its transparency.
+	FormatSmallest Format = iota
+
+	FormatPNG
+
+	// FormatJPEG is lossy; how much so is controlled by Options.Quality. It has no transparency.
+	FormatJPEG
+
+	// FormatGIF quantizes the image to the Plan 9 palette (of 256 colors), with dithering. So it is
+	// lossy for images with other colors. And it has no transparency.
+	FormatGIF
+)
+
+
+// String returns the name of the format.
+func (format Format) String() string {
+	switch format {
+	case FormatSmallest:
+		return "smallest"
+	case FormatPNG:
+		return "png"
+	case FormatJPEG:
+		return "jpeg"
+	case FormatGIF:
+		return "gif"
+	default:
+		return "unknown"
+	}
+}
+
+
+// Options are used to control how imageurl.FromImage() and imageurl.FromParcel() create data URLs.
+//
+// The zero value of Options is valid. It does not resize the image, and uses whichever
+// format results in the smallest data URL.
+type Options struct {
+	// MaxDimension, if greater than zero, is the largest the width and the height
+	// can be. Images that are larger are scaled down (keeping their aspect ratio).
+	// Images are never scaled up.
+	MaxDimension int
+
+	// Format is the image format to encode with.
+	Format Format
+
+	// Quality is the JPEG quality, from 1 to 100. If zero, then 75 is used.
+	// It is ignored for PNG and GIF.
+	Quality int
+
+	// MaxPixels is the most pixels (width × height) that imageurl.FromParcel() will decode an
+	// image with. If zero, then DefaultMaxPixels is used. If negative, then there is no limit.
+	MaxPixels int64
+}
+
+
+// DefaultMaxPixels is the most pixels that imageurl.FromParcel() will decode an image with, if
+// Options.MaxPixels is zero. (Decoded, an image this size takes about 160 MB.)
+const DefaultMaxPixels = 40 * 1000 * 1000
+
+
+const defaultQuality = 75
+
+
+func (options Options) quality() int {
+	switch {
+	case options.Quality <= 0:
+		return defaultQuality
+	case 100 < options.Quality:
+		return 100
+	default:
+		return options.Quality
+	}
+}
+
+
+func (options Options) maxPixels() int64 {
+	switch {
+	case 0 == options.MaxPixels:
+		return DefaultMaxPixels
+	case options.MaxPixels < 0:
+		return -1
+	default:
+		return options.MaxPixels
+	}
+}
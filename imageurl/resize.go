@@ -0,0 +1,75 @@
+package imageurl
+
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+
+// resize returns 'img' scaled down so that neither its width nor its height is larger
+// than 'maxDimension'. If it is already small enough (or 'maxDimension' is not greater
+// than zero) then 'img' is returned as is.
+//
+// Each pixel in the result is the average of the pixels in the original that it covers
+// (a box filter), which works well for scaling down.
+func resize(img image.Image, maxDimension int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if maxDimension <= 0 || (width <= maxDimension && height <= maxDimension) {
+		return img
+	}
+
+	newWidth, newHeight := maxDimension, maxDimension
+	if height < width {
+		newHeight = max(1, (height*maxDimension+width/2)/width)
+	} else {
+		newWidth = max(1, (width*maxDimension+height/2)/height)
+	}
+
+	// Work on a copy in a known pixel format.
+	src := image.NewNRGBA64(image.Rect(0, 0, width, height))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, newWidth, newHeight))
+
+	for y := 0; y < newHeight; y++ {
+		y0 := y * height / newHeight
+		y1 := max(y0+1, (y+1)*height/newHeight)
+
+		for x := 0; x < newWidth; x++ {
+			x0 := x * width / newWidth
+			x1 := max(x0+1, (x+1)*width/newWidth)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := src.NRGBA64At(sx, sy)
+
+					// Weight the colour by alpha, so that transparent pixels do not darken the result.
+					r += uint64(c.R) * uint64(c.A)
+					g += uint64(c.G) * uint64(c.A)
+					b += uint64(c.B) * uint64(c.A)
+					a += uint64(c.A)
+					n++
+				}
+			}
+
+			if 0 == a {
+				dst.SetNRGBA(x, y, color.NRGBA{})
+				continue
+			}
+
+			dst.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(r / a >> 8),
+				G: uint8(g / a >> 8),
+				B: uint8(b / a >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+
+	return dst
+}
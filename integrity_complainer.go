@@ -0,0 +1,56 @@
+package dataurl
+
+
+import (
+	"fmt"
+)
+
+
+// IntegrityComplainer is used to represent a specific kind of BadRequestComplainer error.
+// Specifically, it represents content that does not match an integrity value (such as a
+// Subresource Integrity string) that it was checked against.
+type IntegrityComplainer interface {
+	BadRequestComplainer
+	IntegrityComplainer()
+}
+
+
+// internalIntegrityComplainer is the only underlying implementation that fits the
+// IntegrityComplainer interface, in this library.
+type internalIntegrityComplainer struct {
+	msg string
+}
+
+
+// newIntegrityComplainer creates a new internalIntegrityComplainer (struct) and
+// returns it as a IntegrityComplainer (interface).
+func newIntegrityComplainer(format string, a ...interface{}) IntegrityComplainer {
+	msg := fmt.Sprintf(format, a...)
+
+	err := internalIntegrityComplainer{
+		msg:msg,
+	}
+
+	return &err
+}
+
+
+// Error method is necessary to satisfy the 'error' interface (and the
+// IntegrityComplainer interface).
+func (err *internalIntegrityComplainer) Error() string {
+	return fmt.Sprintf("Bad Request: Integrity: %s", err.msg)
+}
+
+
+// BadRequestComplainer method is necessary to satisfy the 'BadRequestComplainer' interface.
+// It exists to make this error type detectable in a Go type-switch.
+func (err *internalIntegrityComplainer) BadRequestComplainer() {
+	// Nothing here.
+}
+
+
+// IntegrityComplainer method is necessary to satisfy the 'IntegrityComplainer' interface.
+// It exists to make this error type detectable in a Go type-switch.
+func (err *internalIntegrityComplainer) IntegrityComplainer() {
+	// Nothing here.
+}
@@ -0,0 +1,266 @@
+package embedded
+
+
+import (
+	"bytes"
+	"html"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+
+// findCSS returns each url() in the CSS 'css'. 'offset' is where 'css' is in the document; and
+// 'context' is what the CSS is in.
+//
+// Comments and strings (other than the ones in a url()) are skipped over.
+func findCSS(css []byte, offset int, context context) []Match {
+	var matches []Match
+
+	i := 0
+	for i < len(css) {
+		switch css[i] {
+		case '/':
+			if bytes.HasPrefix(css[i:], []byte("/*")) {
+				i = skipPast(css, i+len("/*"), "*/")
+				continue
+			}
+		case '"', '\'':
+			i = skipString(css, i)
+			continue
+		case '\\':
+			i += 2
+			continue
+		case 'u', 'U':
+			if (0 == i || !isNameByte(css[i-1])) && 4 <= len(css[i:]) && strings.EqualFold("url(", string(css[i:i+4])) {
+				match, end, ok := parseURL(css, i+len("url("), context)
+				if ok {
+					match.Start += offset
+					match.End += offset
+					matches = append(matches, match)
+				}
+				i = end
+				continue
+			}
+		}
+		i++
+	}
+
+	return matches
+}
+
+
+// parseURL parses the inside of the url() that starts at 'i' in 'css'. It returns where the url()
+// ends (or where to carry on from, if it is not a url()).
+func parseURL(css []byte, i int, context context) (Match, int, bool) {
+	j := i
+	for j < len(css) && isSpace(css[j]) {
+		j++
+	}
+	if len(css) <= j {
+		return Match{}, i, false
+	}
+
+	var start, end int
+	quote := css[j]
+	quoted := '"' == quote || '\'' == quote
+	if quoted {
+		start = j + 1
+		j = start
+		for j < len(css) && quote != css[j] {
+			if '\n' == css[j] {
+				return Match{}, i, false
+			}
+			if '\\' == css[j] {
+				j++
+			}
+			j++
+		}
+		if len(css) <= j {
+			return Match{}, i, false
+		}
+		end = j
+		j++
+	} else {
+		start = j
+		end = skipUnquotedURL(css, j)
+		if -1 == end {
+			return Match{}, i, false
+		}
+		j = end
+	}
+
+	for j < len(css) && isSpace(css[j]) {
+		j++
+	}
+	if len(css) <= j || ')' != css[j] || start == end {
+		return Match{}, i, false
+	}
+
+	raw := string(css[start:end])
+	if contextCSSInStyleAttribute == context {
+		// The HTML entities in an attribute are decoded before the CSS is parsed.
+		raw = html.UnescapeString(raw)
+
+		if quoted && -1 != indexUnescaped(raw, quote) {
+			return Match{}, i, false
+		}
+		if !quoted && len(raw) != skipUnquotedURL([]byte(raw), 0) {
+			return Match{}, i, false
+		}
+	}
+
+	return Match{
+		Start:   start,
+		End:     end,
+		URL:     unescapeCSS(raw),
+		context: context,
+	}, j+1, true
+}
+
+
+// skipUnquotedURL returns where the unquoted URL that starts at 'i' in 'css' ends; or -1 if there is
+// something that cannot be in an unquoted url() (ex: a quote).
+func skipUnquotedURL(css []byte, i int) int {
+	for i < len(css) && ')' != css[i] && !isSpace(css[i]) {
+		switch css[i] {
+		case '"', '\'', '(':
+			return -1
+		case '\\':
+			i = skipEscape(css, i)
+			continue
+		}
+		i++
+	}
+	if len(css) < i {
+		return -1
+	}
+
+	return i
+}
+
+
+// skipEscape returns where the CSS escape that starts at 'i' in 'css' ends. A hex escape can end with
+// a whitespace.
+func skipEscape(css []byte, i int) int {
+	i++
+
+	hex := i
+	for hex < len(css) && hex-i < 6 && isHexDigit(css[hex]) {
+		hex++
+	}
+	if i == hex {
+		return i + 1
+	}
+
+	switch {
+	case bytes.HasPrefix(css[hex:], []byte("\r\n")):
+		return hex + 2
+	case hex < len(css) && isSpace(css[hex]):
+		return hex + 1
+	default:
+		return hex
+	}
+}
+
+
+// skipString returns where the CSS string that starts at 'i' in 'css' ends. (An unescaped line break
+// also ends it.)
+func skipString(css []byte, i int) int {
+	quote := css[i]
+
+	i++
+	for i < len(css) && quote != css[i] && '\n' != css[i] {
+		if '\\' == css[i] {
+			i++
+		}
+		i++
+	}
+
+	return i + 1
+}
+
+
+// unescapeCSS returns 's' with its CSS escapes unescaped. A backslash followed by 1 to 6 hex digits
+// (and an optional whitespace) is that code point; a backslash followed by a line break is nothing;
+// and a backslash followed by anything else is that.
+func unescapeCSS(s string) string {
+	if -1 == strings.IndexByte(s, '\\') {
+		return s
+	}
+
+	var builder strings.Builder
+
+	for i := 0; i < len(s); {
+		if '\\' != s[i] {
+			builder.WriteByte(s[i])
+			i++
+			continue
+		}
+		i++
+		if len(s) <= i {
+			break
+		}
+
+		hex := i
+		for hex < len(s) && hex-i < 6 && isHexDigit(s[hex]) {
+			hex++
+		}
+		if i < hex {
+			r := utf8.RuneError
+			if n, err := strconv.ParseUint(s[i:hex], 16, 32); nil == err && 0 < n && utf8.ValidRune(rune(n)) {
+				r = rune(n)
+			}
+			builder.WriteRune(r)
+
+			i = hex
+			switch {
+			case strings.HasPrefix(s[i:], "\r\n"):
+				i += 2
+			case i < len(s) && isSpace(s[i]):
+				i++
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(s[i:], "\r\n"):
+			i += 2
+		case '\n' == s[i], '\r' == s[i], '\f' == s[i]:
+			i++
+		default:
+			_, size := utf8.DecodeRuneInString(s[i:])
+			builder.WriteString(s[i:i+size])
+			i += size
+		}
+	}
+
+	return builder.String()
+}
+
+
+// indexUnescaped returns where the first 'b' in 's' (that is not escaped with a backslash) is; or -1.
+func indexUnescaped(s string, b byte) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case b:
+			return i
+		}
+	}
+
+	return -1
+}
+
+
+func isHexDigit(b byte) bool {
+	return ('0' <= b && b <= '9') || ('a' <= b && b <= 'f') || ('A' <= b && b <= 'F')
+}
+
+
+// isNameByte returns whether 'b' can be part of a CSS identifier. (So that "url(" in "myurl(" is not
+// taken to be a url().)
+func isNameByte(b byte) bool {
+	return isASCIILetter(b) || ('0' <= b && b <= '9') || '-' == b || '_' == b || 0x80 <= b
+}
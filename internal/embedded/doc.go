@@ -0,0 +1,30 @@
+/*
+Package embedded finds the URLs (data URLs, and references to other files) that are embedded in
+HTML, SVG, CSS, Markdown and JSON documents; so that they can be rewritten.
+
+It is what the dataurl command, and the packages of go-dataurl that rewrite documents (ex: mhtml),
+have in common. It is internal, so that it is not part of the API of go-dataurl.
+
+Each URL found is reported with where it is (as a byte range), what it is once unescaped, and how
+to escape a replacement for it.
+
+A URL is only reported if it ends where its surrounding attribute, url(), link or string ends. So
+(for example) a data URL with a space in it, in a quoted url(), is reported whole; and something
+that only looks like a URL is not reported at all.
+
+Example Usage
+
+	var spans []embedded.Span
+	for _, match := range embedded.Find(content, embedded.SyntaxHTML) {
+		if !embedded.HasScheme(match.URL, "data:") {
+			continue
+		}
+
+		//...
+
+		spans = append(spans, embedded.Span{match.Start, match.End, match.Escape(replacement)})
+	}
+
+	rewritten := embedded.Replace(content, spans)
+*/
+package embedded
@@ -0,0 +1,220 @@
+package embedded
+
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+// Syntax is the kind of document that URLs are found in.
+type Syntax int
+
+
+const (
+	// SyntaxHTML is an HTML (or SVG) document. URLs are found in the values of attributes, and in
+	// the CSS url()s in style attributes and <style> elements.
+	SyntaxHTML Syntax = iota
+
+	// SyntaxCSS is a CSS style sheet. URLs are found in url()s.
+	SyntaxCSS
+
+	// SyntaxMarkdown is a Markdown document. URLs are found in the destinations of links and images;
+	// and in the HTML in it.
+	SyntaxMarkdown
+
+	// SyntaxJSON is a JSON document. Every string is reported.
+	SyntaxJSON
+)
+
+
+// String returns the name of the syntax.
+func (syntax Syntax) String() string {
+	switch syntax {
+	case SyntaxHTML:
+		return "html"
+	case SyntaxCSS:
+		return "css"
+	case SyntaxMarkdown:
+		return "markdown"
+	case SyntaxJSON:
+		return "json"
+	default:
+		return "unknown"
+	}
+}
+
+
+// SyntaxOf returns the Syntax of the file named 'name', by its file extension. Anything that is not
+// CSS, Markdown or JSON is taken to be HTML (which covers SVG too).
+func SyntaxOf(name string) Syntax {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".css":
+		return SyntaxCSS
+	case ".md", ".markdown":
+		return SyntaxMarkdown
+	case ".json", ".jsonld", ".webmanifest":
+		return SyntaxJSON
+	default:
+		return SyntaxHTML
+	}
+}
+
+
+// context is what a URL is embedded in; which determines how it is escaped.
+type context int
+
+
+const (
+	contextHTMLAttribute context = iota
+	contextHTMLUnquotedAttribute
+	contextCSS
+	contextCSSInStyleElement
+	contextCSSInStyleAttribute
+	contextMarkdown
+	contextJSON
+)
+
+
+// Match is a URL found by embedded.Find().
+type Match struct {
+	// Start and End are where (in the document) the URL is, as it is written (i.e., escaped). Quotes
+	// around it are not included; except for the "<" and ">" around a Markdown link destination.
+	Start int
+	End   int
+
+	// URL is the URL, unescaped. (Ex: "&amp;" in an HTML attribute is "&".)
+	URL string
+
+	// Attribute is the name (lower-cased) of the HTML attribute that the URL is in; ex: "src". For a
+	// CSS url() in a style attribute, it is "style". Otherwise it is empty.
+	Attribute string
+
+	// Element is the name (lower-cased) of the HTML element that Attribute is in; ex: "img". It is
+	// empty if Attribute is.
+	Element string
+
+	context context
+}
+
+
+// Escape returns 'url' escaped so that it can replace the URL that 'match' is; i.e., so that it can
+// be put at match.Start to match.End.
+func (match Match) Escape(url string) string {
+	switch match.context {
+	case contextHTMLAttribute:
+		return dataurl.Escape(url, dataurl.ContextHTMLAttribute)
+	case contextHTMLUnquotedAttribute:
+		// An unquoted attribute value cannot have spaces (etc) in it; so it is quoted.
+		return `"` + dataurl.Escape(url, dataurl.ContextHTMLAttribute) + `"`
+	case contextCSS:
+		return dataurl.Escape(url, dataurl.ContextCSSURL)
+	case contextCSSInStyleElement:
+		// A "</style>" in it would end the <style> element.
+		return strings.ReplaceAll(dataurl.Escape(url, dataurl.ContextCSSURL), "<", `\3c `)
+	case contextCSSInStyleAttribute:
+		return dataurl.Escape(dataurl.Escape(url, dataurl.ContextCSSURL), dataurl.ContextHTMLAttribute)
+	case contextMarkdown:
+		return dataurl.Escape(url, dataurl.ContextMarkdownLink)
+	case contextJSON:
+		return escapeJSON(url)
+	default:
+		return url
+	}
+}
+
+
+// Find returns the URLs embedded in 'content'; which is a document with the syntax 'syntax'. They
+// are sorted by where they are, and do not overlap.
+func Find(content []byte, syntax Syntax) []Match {
+	var matches []Match
+
+	switch syntax {
+	case SyntaxHTML:
+		matches = findHTML(content)
+	case SyntaxCSS:
+		matches = findCSS(content, 0, contextCSS)
+	case SyntaxMarkdown:
+		matches = append(findMarkdown(content), findHTML(content)...)
+	case SyntaxJSON:
+		matches = findJSON(content)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Start < matches[j].Start
+	})
+
+	// Something that looks like a URL inside of another one (ex: a Markdown link in an HTML attribute)
+	// is part of that one.
+	var tidied []Match
+	end := 0
+	for _, match := range matches {
+		if match.Start < end {
+			continue
+		}
+
+		tidied = append(tidied, match)
+		end = match.End
+	}
+
+	return tidied
+}
+
+
+// findJSON returns each string in the JSON document 'content'.
+func findJSON(content []byte) []Match {
+	var matches []Match
+
+	i := 0
+	for i < len(content) {
+		if '"' != content[i] {
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(content) && '"' != content[end] {
+			if '\\' == content[end] {
+				end++
+			}
+			end++
+		}
+		if len(content) <= end {
+			break
+		}
+
+		var s string
+		if err := json.Unmarshal(content[i:end+1], &s); nil == err {
+			matches = append(matches, Match{
+				Start:   i + 1,
+				End:     end,
+				URL:     s,
+				context: contextJSON,
+			})
+		}
+
+		i = end + 1
+	}
+
+	return matches
+}
+
+
+// escapeJSON returns 's' escaped for the inside of a JSON string. "<", ">" and "&" are not escaped;
+// so that data URLs stay readable.
+func escapeJSON(s string) string {
+	var buffer bytes.Buffer
+
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); nil != err {
+		return s
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(strings.TrimSuffix(buffer.String(), "\n"), `"`), `"`)
+}
@@ -0,0 +1,224 @@
+package embedded
+
+
+import (
+	"testing"
+)
+
+
+func TestFind(t *testing.T) {
+
+	const svg = `data:image/svg+xml;charset=utf8,%3Csvg xmlns='http://www.w3.org/2000/svg'%3E%3C/svg%3E`
+
+	tests := []struct{
+		Content  string
+		Syntax   Syntax
+		Expected []string
+		Raw      []string
+	}{
+		{
+			Content:  `<img src="data:image/gif;base64,R0lGODlhAQABAAAAACw=" alt="">`,
+			Syntax:   SyntaxHTML,
+			Expected: []string{`data:image/gif;base64,R0lGODlhAQABAAAAACw=`, ``},
+			Raw:      []string{`data:image/gif;base64,R0lGODlhAQABAAAAACw=`, ``},
+		},
+		{
+			Content:  `<a href=data:,Hi%21>hi</a><a title='a &amp; b'>`,
+			Syntax:   SyntaxHTML,
+			Expected: []string{`data:,Hi%21`, `a & b`},
+			Raw:      []string{`data:,Hi%21`, `a &amp; b`},
+		},
+		{
+			Content:  `.a{background:url("` + svg + `")}`,
+			Syntax:   SyntaxCSS,
+			Expected: []string{svg},
+			Raw:      []string{svg},
+		},
+		{
+			Content:  `.a{background:url(data:,a\)b)} /* url(comment.png) */ .b{content:"url(string.png)"} .c{background:URL( c.png )}`,
+			Syntax:   SyntaxCSS,
+			Expected: []string{`data:,a)b`, `c.png`},
+			Raw:      []string{`data:,a\)b`, `c.png`},
+		},
+		{
+			Content:  `.a{background:url(data:,a b)} .b{background:url("data:,c)`,
+			Syntax:   SyntaxCSS,
+			Expected: nil,
+			Raw:      nil,
+		},
+		{
+			Content:  `.a{background:url(data:,\3c svg\20 \3e)}`,
+			Syntax:   SyntaxCSS,
+			Expected: []string{`data:,<svg >`},
+			Raw:      []string{`data:,\3c svg\20 \3e`},
+		},
+		{
+			Content:  `<style>.a{background:url("` + svg + `")}</style><div style="background:url('data:,a&amp;b\20 c')"></div>`,
+			Syntax:   SyntaxHTML,
+			Expected: []string{svg, `data:,a&b c`},
+			Raw:      []string{svg, `data:,a&amp;b\20 c`},
+		},
+		{
+			Content:  `<div style="background:url(&quot;data:,a b&quot;)"></div><!-- <img src="comment.png"> --><script>x = "<img src='script.png'>"</script>`,
+			Syntax:   SyntaxHTML,
+			Expected: nil,
+			Raw:      nil,
+		},
+		{
+			Content:  "![dot](data:text/plain;charset=utf-8,Hello%20world)\n[a](<data:,a b> \"Title\") [b](data:,\\(b\\))",
+			Syntax:   SyntaxMarkdown,
+			Expected: []string{`data:text/plain;charset=utf-8,Hello%20world`, `data:,a b`, `data:,(b)`},
+			Raw:      []string{`data:text/plain;charset=utf-8,Hello%20world`, `<data:,a b>`, `data:,\(b\)`},
+		},
+		{
+			Content:  "[a](data:,a b) <img src=\"dot.png\">",
+			Syntax:   SyntaxMarkdown,
+			Expected: []string{`dot.png`},
+			Raw:      []string{`dot.png`},
+		},
+		{
+			Content:  `{"avatar":"data:image/svg+xml,%3Csvg xmlns=\"http://www.w3.org/2000/svg\"/%3E","name":"Joe"}`,
+			Syntax:   SyntaxJSON,
+			Expected: []string{`avatar`, `data:image/svg+xml,%3Csvg xmlns="http://www.w3.org/2000/svg"/%3E`, `name`, `Joe`},
+			Raw:      []string{`avatar`, `data:image/svg+xml,%3Csvg xmlns=\"http://www.w3.org/2000/svg\"/%3E`, `name`, `Joe`},
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		actual := Find([]byte(test.Content), test.Syntax)
+
+		if expected, actual := len(test.Expected), len(actual); expected != actual {
+			t.Errorf("For test #%d, expected %d URLs, but actually got %d: %#v", testNumber, expected, actual, Find([]byte(test.Content), test.Syntax))
+			continue
+		}
+
+		for i, match := range actual {
+			if expected, actual := test.Expected[i], match.URL; expected != actual {
+				t.Errorf("For test #%d and URL #%d, expected %q, but actually got %q.", testNumber, i, expected, actual)
+				continue
+			}
+			if expected, actual := test.Raw[i], test.Content[match.Start:match.End]; expected != actual {
+				t.Errorf("For test #%d and URL #%d, expected it to be at %q, but actually was at %q.", testNumber, i, expected, actual)
+				continue
+			}
+		}
+	}
+}
+
+
+func TestFindElement(t *testing.T) {
+	content := `<IMG SRC="a.png"><td background=b.png style="background:url(c.png)"></td><style>.d{background:url(d.png)}</style>`
+
+	expected := []struct{
+		Element   string
+		Attribute string
+	}{
+		{"img", "src"},
+		{"td", "background"},
+		{"td", "style"},
+		{"", ""},
+	}
+
+	matches := Find([]byte(content), SyntaxHTML)
+	if len(expected) != len(matches) {
+		t.Fatalf("Expected %d URLs, but actually got %d: %#v", len(expected), len(matches), matches)
+	}
+
+	for i, match := range matches {
+		if expected, actual := expected[i].Element, match.Element; expected != actual {
+			t.Errorf("For URL #%d, expected element %q, but actually got %q.", i, expected, actual)
+		}
+		if expected, actual := expected[i].Attribute, match.Attribute; expected != actual {
+			t.Errorf("For URL #%d, expected attribute %q, but actually got %q.", i, expected, actual)
+		}
+	}
+}
+
+
+func TestMatchEscape(t *testing.T) {
+
+	const svg = `data:image/svg+xml,<svg xmlns='http://www.w3.org/2000/svg'></svg>`
+
+	tests := []struct{
+		Content string
+		Syntax  Syntax
+	}{
+		{
+			Content: `<img src="x.png">`,
+			Syntax:  SyntaxHTML,
+		},
+		{
+			Content: `<img src=x.png>`,
+			Syntax:  SyntaxHTML,
+		},
+		{
+			Content: `.a{background:url(x.png)}`,
+			Syntax:  SyntaxCSS,
+		},
+		{
+			Content: `<style>.a{background:url('x.png')}</style>`,
+			Syntax:  SyntaxHTML,
+		},
+		{
+			Content: `<div style="background:url(x.png)"></div>`,
+			Syntax:  SyntaxHTML,
+		},
+		{
+			Content: `![x](x.png)`,
+			Syntax:  SyntaxMarkdown,
+		},
+		{
+			Content: `{"x":"x.png"}`,
+			Syntax:  SyntaxJSON,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		var spans []Span
+		for _, match := range Find([]byte(test.Content), test.Syntax) {
+			if "x.png" != match.URL {
+				continue
+			}
+			spans = append(spans, Span{match.Start, match.End, match.Escape(svg)})
+		}
+		if 1 != len(spans) {
+			t.Errorf("For test #%d, expected 1 URL to replace, but actually got %d.", testNumber, len(spans))
+			continue
+		}
+
+		rewritten := Replace([]byte(test.Content), spans)
+
+		var found []string
+		for _, match := range Find(rewritten, test.Syntax) {
+			if HasScheme(match.URL, "data:") {
+				found = append(found, match.URL)
+			}
+		}
+
+		if expected, actual := 1, len(found); expected != actual {
+			t.Errorf("For test #%d, expected %d data URL in the rewritten document, but actually got %d: %q", testNumber, expected, actual, rewritten)
+			continue
+		}
+		if expected, actual := svg, found[0]; expected != actual {
+			t.Errorf("For test #%d, expected %q, but actually got %q.", testNumber, expected, actual)
+			t.Logf("REWRITTEN: %s", rewritten)
+			continue
+		}
+	}
+}
+
+
+func TestReplace(t *testing.T) {
+	content := []byte(`<img src="a.png"><img src="b.png">`)
+
+	spans := []Span{
+		{Start: 10, End: 15, Text: "data:,A"},
+		{Start: 27, End: 32, Text: "data:,B"},
+	}
+
+	if expected, actual := `<img src="data:,A"><img src="data:,B">`, string(Replace(content, spans)); expected != actual {
+		t.Errorf("Expected %q, but actually got %q.", expected, actual)
+	}
+}
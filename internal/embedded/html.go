@@ -0,0 +1,194 @@
+package embedded
+
+
+import (
+	"bytes"
+	"html"
+	"strings"
+)
+
+
+// findHTML returns the value of each attribute in the HTML (or SVG) document 'content', and each
+// CSS url() in its style attributes and <style> elements.
+//
+// Comments, CDATA sections, and the contents of <script> elements are skipped over.
+func findHTML(content []byte) []Match {
+	var matches []Match
+
+	i := 0
+	for i < len(content) {
+		index := bytes.IndexByte(content[i:], '<')
+		if -1 == index {
+			break
+		}
+		i += index
+
+		rest := content[i:]
+		switch {
+		case bytes.HasPrefix(rest, []byte("<!--")):
+			i = skipPast(content, i+len("<!--"), "-->")
+		case bytes.HasPrefix(rest, []byte("<![CDATA[")):
+			i = skipPast(content, i+len("<![CDATA["), "]]>")
+		case 2 <= len(rest) && ('!' == rest[1] || '?' == rest[1] || '/' == rest[1]):
+			i = skipPast(content, i+2, ">")
+		case 2 <= len(rest) && isASCIILetter(rest[1]):
+			name, attributes, end, ok := parseTag(content, i)
+			if !ok {
+				return matches
+			}
+			matches = append(matches, attributes...)
+			i = end
+
+			if "script" != name && "style" != name {
+				continue
+			}
+
+			// The contents of <script> and <style> elements are raw text; HTML in them is not HTML.
+			closing := indexFold(content[i:], "</" + name)
+			if -1 == closing {
+				closing = len(content) - i
+			}
+			if "style" == name {
+				matches = append(matches, findCSS(content[i:i+closing], i, contextCSSInStyleElement)...)
+			}
+			i += closing
+		default:
+			i++
+		}
+	}
+
+	return matches
+}
+
+
+// parseTag parses the start tag at 'start' in 'content'. It returns the (lower-cased) name of the
+// element, its attribute values (and the url()s in its style attribute), and where the tag ends.
+//
+// It returns false if the tag does not end.
+func parseTag(content []byte, start int) (string, []Match, int, bool) {
+	var matches []Match
+
+	i := start + 1
+	for i < len(content) && !isSpace(content[i]) && '/' != content[i] && '>' != content[i] {
+		i++
+	}
+	name := strings.ToLower(string(content[start+1:i]))
+
+	for {
+		for i < len(content) && (isSpace(content[i]) || '/' == content[i]) {
+			i++
+		}
+		if len(content) <= i {
+			return "", nil, 0, false
+		}
+		if '>' == content[i] {
+			return name, matches, i+1, true
+		}
+
+		nameStart := i
+		for i < len(content) && !isSpace(content[i]) && '/' != content[i] && '>' != content[i] && '=' != content[i] {
+			i++
+		}
+		attribute := strings.ToLower(string(content[nameStart:i]))
+
+		for i < len(content) && isSpace(content[i]) {
+			i++
+		}
+		if len(content) <= i || '=' != content[i] {
+			// An attribute without a value (ex: <input disabled>).
+			continue
+		}
+		i++
+		for i < len(content) && isSpace(content[i]) {
+			i++
+		}
+		if len(content) <= i {
+			return "", nil, 0, false
+		}
+
+		var valueStart, valueEnd int
+		quoted := '"' == content[i] || '\'' == content[i]
+		if quoted {
+			quote := content[i]
+
+			end := bytes.IndexByte(content[i+1:], quote)
+			if -1 == end {
+				return "", nil, 0, false
+			}
+
+			valueStart, valueEnd = i+1, i+1+end
+			i = valueEnd + 1
+		} else {
+			valueStart = i
+			for i < len(content) && !isSpace(content[i]) && '>' != content[i] {
+				i++
+			}
+			valueEnd = i
+		}
+
+		if "style" == attribute {
+			// An unquoted style attribute cannot have a url() with a space in it; so it is left alone.
+			if quoted {
+				for _, match := range findCSS(content[valueStart:valueEnd], valueStart, contextCSSInStyleAttribute) {
+					match.Attribute = attribute
+					match.Element = name
+					matches = append(matches, match)
+				}
+			}
+			continue
+		}
+
+		context := contextHTMLAttribute
+		if !quoted {
+			context = contextHTMLUnquotedAttribute
+		}
+
+		matches = append(matches, Match{
+			Start:     valueStart,
+			End:       valueEnd,
+			URL:       strings.TrimSpace(html.UnescapeString(string(content[valueStart:valueEnd]))),
+			Attribute: attribute,
+			Element:   name,
+			context:   context,
+		})
+	}
+}
+
+
+// skipPast returns where (in 'content') the first 'end' (at or after 'i') ends; or the end of 'content'
+// if there isn't one.
+func skipPast(content []byte, i int, end string) int {
+	index := bytes.Index(content[i:], []byte(end))
+	if -1 == index {
+		return len(content)
+	}
+
+	return i + index + len(end)
+}
+
+
+// indexFold is like bytes.Index(), except that ASCII letters are matched case-insensitively.
+func indexFold(content []byte, s string) int {
+	for i := 0; i+len(s) <= len(content); i++ {
+		if strings.EqualFold(s, string(content[i:i+len(s)])) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+
+func isASCIILetter(b byte) bool {
+	return ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+
+func isSpace(b byte) bool {
+	switch b {
+	case ' ', '\t', '\r', '\n', '\f':
+		return true
+	default:
+		return false
+	}
+}
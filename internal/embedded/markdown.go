@@ -0,0 +1,141 @@
+package embedded
+
+
+import (
+	"bytes"
+	"strings"
+)
+
+
+// findMarkdown returns the destination of each (inline) link and image in the Markdown 'content'.
+// For example: [text](destination "title") and ![alt text](<destination with spaces>)
+func findMarkdown(content []byte) []Match {
+	var matches []Match
+
+	i := 0
+	for {
+		index := bytes.Index(content[i:], []byte("]("))
+		if -1 == index {
+			break
+		}
+		i += index + len("](")
+
+		match, ok := parseDestination(content, i)
+		if ok {
+			matches = append(matches, match)
+		}
+	}
+
+	return matches
+}
+
+
+// parseDestination parses the destination (and optional title) of the link at 'i' in 'content'; which
+// is just after the "](".
+func parseDestination(content []byte, i int) (Match, bool) {
+	for i < len(content) && isSpace(content[i]) {
+		i++
+	}
+	if len(content) <= i {
+		return Match{}, false
+	}
+
+	start := i
+	var url string
+	if '<' == content[i] {
+		i++
+		for i < len(content) && '>' != content[i] {
+			switch content[i] {
+			case '\n', '<':
+				return Match{}, false
+			case '\\':
+				i++
+			}
+			i++
+		}
+		if len(content) <= i {
+			return Match{}, false
+		}
+		url = unescapeMarkdown(string(content[start+1:i]))
+		i++
+	} else {
+		depth := 0
+		loop: for i < len(content) && ' ' < content[i] {
+			switch content[i] {
+			case '\\':
+				i++
+			case '(':
+				depth++
+			case ')':
+				if 0 == depth {
+					break loop
+				}
+				depth--
+			}
+			i++
+		}
+		if len(content) < i || start == i {
+			return Match{}, false
+		}
+		url = unescapeMarkdown(string(content[start:i]))
+	}
+	end := i
+
+	// The title.
+	for i < len(content) && isSpace(content[i]) {
+		i++
+	}
+	if i < len(content) && end < i && ('"' == content[i] || '\'' == content[i] || '(' == content[i]) {
+		closing := content[i]
+		if '(' == closing {
+			closing = ')'
+		}
+
+		i++
+		for i < len(content) && closing != content[i] {
+			if '\\' == content[i] {
+				i++
+			}
+			i++
+		}
+		i++
+
+		for i < len(content) && isSpace(content[i]) {
+			i++
+		}
+	}
+	if len(content) <= i || ')' != content[i] {
+		return Match{}, false
+	}
+
+	return Match{
+		Start:   start,
+		End:     end,
+		URL:     url,
+		context: contextMarkdown,
+	}, true
+}
+
+
+// unescapeMarkdown returns 's' with its backslash escapes (of ASCII punctuation) unescaped.
+func unescapeMarkdown(s string) string {
+	if -1 == strings.IndexByte(s, '\\') {
+		return s
+	}
+
+	var builder strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if '\\' == s[i] && i+1 < len(s) && isASCIIPunctuation(s[i+1]) {
+			i++
+		}
+		builder.WriteByte(s[i])
+	}
+
+	return builder.String()
+}
+
+
+func isASCIIPunctuation(b byte) bool {
+	return ('!' <= b && b <= '/') || (':' <= b && b <= '@') || ('[' <= b && b <= '`') || ('{' <= b && b <= '~')
+}
@@ -0,0 +1,28 @@
+package embedded
+
+
+// Span is a part of a document that is to be replaced.
+type Span struct {
+	Start int
+	End   int
+	Text  string
+}
+
+
+// Replace returns 'content' with each span (in 'spans') replaced by its Text.
+//
+// 'spans' must be sorted, and must not overlap. (Which they are, if they are made from what
+// embedded.Find() returns.)
+func Replace(content []byte, spans []Span) []byte {
+	var result []byte
+
+	previous := 0
+	for _, span := range spans {
+		result = append(result, content[previous:span.Start]...)
+		result = append(result, span.Text...)
+		previous = span.End
+	}
+	result = append(result, content[previous:]...)
+
+	return result
+}
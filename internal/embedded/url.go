@@ -0,0 +1,49 @@
+package embedded
+
+
+import (
+	"strings"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+// HasScheme returns whether the URL 'value' has the scheme 'scheme' (ex: "data:"). Schemes are
+// case-insensitive; and whitespace around 'value' is ignored.
+func HasScheme(value string, scheme string) bool {
+	value = strings.TrimSpace(value)
+	return len(scheme) <= len(value) && strings.EqualFold(scheme, value[:len(scheme)])
+}
+
+
+// Parse is like dataurl.Parse(), except that (as in a browser) the scheme is case-insensitive (ex:
+// "DATA:"), and whitespace around 'value' is ignored.
+func Parse(value string) (dataurl.Parcel, error) {
+	value = strings.TrimSpace(value)
+	if HasScheme(value, "data:") {
+		value = "data:" + value[len("data:"):]
+	}
+
+	return dataurl.Parse(value)
+}
+
+
+// ExtensionFor returns the file extension (including the ".") to use for a file with the media type
+// 'mediaType'; or ".bin" if there isn't one.
+func ExtensionFor(mediaType string) string {
+	if extension := dataurl.ExtensionFor(mediaType); "" != extension {
+		return extension
+	}
+
+	return ".bin"
+}
+
+
+// NormalizeContentID returns the Content-ID 'contentID' without the "<" and ">" around it (if it has
+// them), and without whitespace.
+func NormalizeContentID(contentID string) string {
+	contentID = strings.TrimSpace(contentID)
+	contentID = strings.TrimPrefix(contentID, "<")
+	contentID = strings.TrimSuffix(contentID, ">")
+	return contentID
+}
@@ -0,0 +1,34 @@
+/*
+Package jsonurl finds (and rewrites) the data URLs in JSON documents.
+
+It streams through a JSON document (using encoding/json's Decoder.Token), so the whole document
+is never loaded into memory at once. Each string value that dataurl.Parse() accepts is reported,
+together with its JSON Pointer (as defined by RFC 6901).
+
+Example Usage
+
+	err := jsonurl.Walk(file, func(pointer string, parcel dataurl.Parcel) error {
+		fmt.Printf("%s: %s (%d bytes)\n", pointer, parcel.MediaType(), dataurl.Len(parcel))
+		return nil
+	})
+	if nil != err {
+		//@TODO
+	}
+
+Another Example Usage
+
+	// Replace each image with a link to where it was offloaded to; and remove everything else.
+	err := jsonurl.Rewrite(os.Stdout, file, func(pointer string, parcel dataurl.Parcel) (interface{}, error) {
+		if !strings.HasPrefix(parcel.MediaType(), "image/") {
+			return jsonurl.Remove, nil
+		}
+
+		location, err := offload(parcel)
+		if nil != err {
+			return nil, err
+		}
+
+		return location, nil
+	})
+*/
+package jsonurl
@@ -0,0 +1,272 @@
+package jsonurl
+
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+// sentinel is the type of jsonurl.Keep and jsonurl.Remove.
+type sentinel struct {
+	name string
+}
+
+
+var (
+	// Keep, returned from the func passed to jsonurl.Rewrite(), keeps the data URL as it is.
+	Keep interface{} = &sentinel{"keep"}
+
+	// Remove, returned from the func passed to jsonurl.Rewrite(), removes the data URL. In an
+	// object, the member (both name and value) is removed. In an array, the element is removed.
+	Remove interface{} = &sentinel{"remove"}
+)
+
+
+var (
+	errNilFunc = errors.New("jsonurl: nil func")
+)
+
+
+// Walk reads a JSON document from 'r', and calls 'fn' with each string value (in it) that is a data URL;
+// together with the JSON Pointer (as defined by RFC 6901) of that value.
+//
+// The JSON Pointer of the whole document is the empty string. (So if the whole document is a single
+// string that is a data URL, then the JSON Pointer is "".)
+//
+// If 'fn' returns an error, then Walk stops, and returns that error.
+func Walk(r io.Reader, fn func(pointer string, parcel dataurl.Parcel) error) error {
+	if nil == fn {
+		return errNilFunc
+	}
+
+	return walk(r, nil, func(pointer string, parcel dataurl.Parcel) (interface{}, error) {
+		return Keep, fn(pointer, parcel)
+	})
+}
+
+
+// Rewrite reads a JSON document from 'r', and writes it to 'w'; replacing each string value that is a
+// data URL with whatever 'fn' returns for it.
+//
+// 'fn' is called with the JSON Pointer (as defined by RFC 6901) of each data URL, and what it returns is
+// either: jsonurl.Keep (to keep the data URL as it is), jsonurl.Remove (to remove it), or any other value
+// (which is encoded with encoding/json, and replaces the data URL).
+//
+// The JSON Pointers are those of the document read from 'r'. (Removing an element of an array changes the
+// index of the elements after it, in the document written to 'w'.)
+//
+// What is written to 'w' is compact; without any whitespace between tokens. Numbers are written exactly
+// as they were read.
+//
+// If 'fn' returns an error, then Rewrite stops, and returns that error.
+func Rewrite(w io.Writer, r io.Reader, fn func(pointer string, parcel dataurl.Parcel) (interface{}, error)) error {
+	if nil == fn {
+		return errNilFunc
+	}
+
+	writer := bufio.NewWriter(w)
+
+	if err := walk(r, writer, fn); nil != err {
+		return err
+	}
+
+	return writer.Flush()
+}
+
+
+// frame is an object or array that is being walked.
+type frame struct {
+	object    bool
+	index     int    // For an array, the index of the next element.
+	name      string // For an object, the name of the current member.
+	expectKey bool   // For an object, whether the next token is the name of a member.
+	written   int    // How many members or elements have been written.
+}
+
+
+// walk does the work for Walk and Rewrite. If 'writer' is nil, then nothing is written.
+func walk(r io.Reader, writer *bufio.Writer, fn func(pointer string, parcel dataurl.Parcel) (interface{}, error)) error {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var stack []*frame
+	topLevelValues := 0
+
+	for {
+		token, err := decoder.Token()
+		if io.EOF == err {
+			break
+		}
+		if nil != err {
+			return fmt.Errorf("jsonurl: could not read JSON: %w", err)
+		}
+
+		var top *frame
+		if 0 < len(stack) {
+			top = stack[len(stack)-1]
+		}
+
+		// The end of an object or array.
+		if delim, ok := token.(json.Delim); ok && ('}' == delim || ']' == delim) {
+			stack = stack[:len(stack)-1]
+			if err := writeString(writer, delim.String()); nil != err {
+				return err
+			}
+			afterValue(stack)
+			continue
+		}
+
+		// The name of a member of an object. It is written along with the value.
+		if nil != top && top.object && top.expectKey {
+			top.name, _ = token.(string)
+			top.expectKey = false
+			continue
+		}
+
+		var value interface{} = token
+
+		if s, ok := token.(string); ok && strings.HasPrefix(s, "data:") {
+			if parcel, err := dataurl.Parse(s); nil == err {
+				replacement, err := fn(pointerOf(stack), parcel)
+				if nil != err {
+					return err
+				}
+
+				if Remove == replacement {
+					afterValue(stack)
+					continue
+				}
+				if Keep != replacement {
+					value = replacement
+				}
+			}
+		}
+
+		if nil != top {
+			if 0 < top.written {
+				if err := writeString(writer, ","); nil != err {
+					return err
+				}
+			}
+			if top.object {
+				if err := writeJSON(writer, top.name); nil != err {
+					return err
+				}
+				if err := writeString(writer, ":"); nil != err {
+					return err
+				}
+			}
+			top.written++
+		} else {
+			// Top-level values (of a stream of JSON documents) are each put on their own line.
+			if 0 < topLevelValues {
+				if err := writeString(writer, "\n"); nil != err {
+					return err
+				}
+			}
+			topLevelValues++
+		}
+
+		if delim, ok := value.(json.Delim); ok {
+			if err := writeString(writer, delim.String()); nil != err {
+				return err
+			}
+			stack = append(stack, &frame{
+				object:    '{' == delim,
+				expectKey: '{' == delim,
+			})
+			continue
+		}
+
+		if err := writeJSON(writer, value); nil != err {
+			return err
+		}
+		afterValue(stack)
+	}
+
+	return nil
+}
+
+
+// afterValue updates the innermost object or array (if any) in 'stack', after one of its values.
+func afterValue(stack []*frame) {
+	if len(stack) < 1 {
+		return
+	}
+
+	top := stack[len(stack)-1]
+	if top.object {
+		top.expectKey = true
+	} else {
+		top.index++
+	}
+}
+
+
+// pointerOf returns the JSON Pointer (as defined by RFC 6901) of the current value.
+func pointerOf(stack []*frame) string {
+	var builder strings.Builder
+
+	for _, f := range stack {
+		builder.WriteByte('/')
+		if f.object {
+			builder.WriteString(escapePointerToken(f.name))
+		} else {
+			builder.WriteString(strconv.Itoa(f.index))
+		}
+	}
+
+	return builder.String()
+}
+
+
+var pointerTokenReplacer = strings.NewReplacer("~", "~0", "/", "~1")
+
+
+// escapePointerToken escapes 'token' (ex: the name of a member of an object) for a JSON Pointer.
+func escapePointerToken(token string) string {
+	return pointerTokenReplacer.Replace(token)
+}
+
+
+func writeString(writer *bufio.Writer, s string) error {
+	if nil == writer {
+		return nil
+	}
+
+	_, err := writer.WriteString(s)
+	return err
+}
+
+
+// writeJSON writes 'value' to 'writer', encoded as JSON. Unlike json.Marshal(), "<", ">" and "&" are
+// not escaped; so that strings are written as they were read.
+func writeJSON(writer *bufio.Writer, value interface{}) error {
+	if nil == writer {
+		return nil
+	}
+
+	if number, ok := value.(json.Number); ok {
+		_, err := writer.WriteString(number.String())
+		return err
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); nil != err {
+		return fmt.Errorf("jsonurl: could not encode replacement: %w", err)
+	}
+
+	_, err := writer.Write(bytes.TrimSuffix(buffer.Bytes(), []byte("\n")))
+	return err
+}
@@ -0,0 +1,146 @@
+package jsonurl
+
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+const document = `{
+	"name": "Joe <joe@example.com>",
+	"age": 42.50,
+	"avatar": "data:image/gif;base64,R0lGODlhAQABAAAAACw=",
+	"notes": "data: not a data URL",
+	"a/b~c": "data:,Hello%20world!",
+	"attachments": [
+		{"file": "data:text/plain;charset=utf-8,hi", "size": 2},
+		null,
+		"data:,second"
+	],
+	"nested": {"deeper": [[true, "data:;base64,SGk="]]}
+}`
+
+
+func TestWalk(t *testing.T) {
+	type found struct {
+		Pointer string
+		Content string
+	}
+
+	var actual []found
+	err := Walk(strings.NewReader(document), func(pointer string, parcel dataurl.Parcel) error {
+		actual = append(actual, found{pointer, parcel.String()})
+		return nil
+	})
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	expected := []found{
+		{"/avatar", "GIF89a\x01\x00\x01\x00\x00\x00\x00,"},
+		{"/a~1b~0c", "Hello world!"},
+		{"/attachments/0/file", "hi"},
+		{"/attachments/2", "second"},
+		{"/nested/deeper/0/1", "Hi"},
+	}
+
+	if len(expected) != len(actual) {
+		t.Fatalf("Expected %d data URLs, but actually got %d: %v", len(expected), len(actual), actual)
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			t.Errorf("For data URL #%d, expected %v, but actually got %v.", i, expected[i], actual[i])
+		}
+	}
+}
+
+
+func TestWalkTopLevel(t *testing.T) {
+	var pointers []string
+	err := Walk(strings.NewReader(`"data:,a" "data:,b"`), func(pointer string, parcel dataurl.Parcel) error {
+		pointers = append(pointers, pointer)
+		return nil
+	})
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if expected, actual := `"",""`, `"`+strings.Join(pointers, `","`)+`"`; expected != actual {
+		t.Errorf("Expected pointers %s, but actually got %s.", expected, actual)
+	}
+}
+
+
+func TestWalkError(t *testing.T) {
+	stop := errors.New("stop")
+
+	count := 0
+	err := Walk(strings.NewReader(document), func(pointer string, parcel dataurl.Parcel) error {
+		count++
+		return stop
+	})
+	if stop != err {
+		t.Errorf("Expected the error from the func, but actually got: %v", err)
+	}
+	if expected, actual := 1, count; expected != actual {
+		t.Errorf("Expected the func to be called %d time, but actually was %d.", expected, actual)
+	}
+
+	if err := Walk(strings.NewReader(`{"a": [1, 2}`), func(string, dataurl.Parcel) error { return nil }); nil == err {
+		t.Errorf("Expected an error for bad JSON, but did not actually get one.")
+	}
+}
+
+
+func TestRewrite(t *testing.T) {
+	var buffer bytes.Buffer
+
+	err := Rewrite(&buffer, strings.NewReader(document), func(pointer string, parcel dataurl.Parcel) (interface{}, error) {
+		switch pointer {
+		case "/avatar":
+			return map[string]interface{}{"href": "https://cdn.example.com/avatar.gif", "bytes": dataurl.Len(parcel)}, nil
+		case "/attachments/0/file", "/attachments/2":
+			return Remove, nil
+		case "/nested/deeper/0/1":
+			return nil, nil
+		default:
+			return Keep, nil
+		}
+	})
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	expected := `{"name":"Joe <joe@example.com>","age":42.50,` +
+		`"avatar":{"bytes":14,"href":"https://cdn.example.com/avatar.gif"},` +
+		`"notes":"data: not a data URL",` +
+		`"a/b~c":"data:,Hello%20world!",` +
+		`"attachments":[{"size":2},null],` +
+		`"nested":{"deeper":[[true,null]]}}`
+	if actual := buffer.String(); expected != actual {
+		t.Errorf("Expected:\n%s\nbut actually got:\n%s", expected, actual)
+	}
+}
+
+
+func TestRewriteKeepAll(t *testing.T) {
+	var buffer bytes.Buffer
+
+	const compact = `[{"a":"data:,x","b":[]},{},"",-0.0e+1,false,"data:,y"]` + "\n" + `"data:,z"`
+
+	err := Rewrite(&buffer, strings.NewReader(compact), func(string, dataurl.Parcel) (interface{}, error) {
+		return Keep, nil
+	})
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if expected, actual := compact, buffer.String(); expected != actual {
+		t.Errorf("Expected:\n%s\nbut actually got:\n%s", expected, actual)
+	}
+}
@@ -0,0 +1,256 @@
+package jupyterurl
+
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+// Bundle is a MIME bundle; the "data" member of a display_data or execute_result output. It maps
+// media types (without parameters) to values, as they are in the .ipynb file.
+//
+// A Bundle can be a field of a struct that a notebook is decoded into (with encoding/json), and
+// encoded back again, as is.
+type Bundle map[string]json.RawMessage
+
+
+// Parcel returns the value (in the bundle) for the media type 'mediaType', decoded.
+//
+// Text (and JSON) media types are given a "charset=utf-8" parameter, since that is what
+// they are in a notebook.
+func (bundle Bundle) Parcel(mediaType string) (dataurl.Parcel, error) {
+	mimeType, _, err := mime.ParseMediaType(mediaType)
+	if nil != err {
+		return nil, fmt.Errorf("jupyterurl: bad media type %q: %w", mediaType, err)
+	}
+
+	value, ok := bundle[mimeType]
+	if !ok {
+		return nil, fmt.Errorf("jupyterurl: no %q in MIME bundle", mimeType)
+	}
+
+	return decode(mimeType, value)
+}
+
+
+// DataURL returns a data URL for the value (in the bundle) for the media type 'mediaType'.
+//
+// Values that the notebook stores base64 encoded (ex: "image/png") are put into base64 encoded data
+// URLs. Text (and JSON) values are put into whichever is shorter; see dataurl.EncodeShortest().
+//
+// Example usage:
+//
+//	dataURL, err := output.Data.DataURL("image/png")
+//	if nil != err {
+//		//@TODO
+//	}
+func (bundle Bundle) DataURL(mediaType string) (string, error) {
+	parcel, err := bundle.Parcel(mediaType)
+	if nil != err {
+		return "", err
+	}
+
+	// Bundle.Parcel() already checked that 'mediaType' parses.
+	if mimeType, _, _ := mime.ParseMediaType(mediaType); !isJSON(mimeType) && !isText(mimeType) {
+		return dataurl.Encode(mimeType, dataurl.UnsafeBytes(parcel), dataurl.EncodingBase64)
+	}
+
+	return dataurl.EncodeShortest(parcel.MediaType(), dataurl.UnsafeBytes(parcel))
+}
+
+
+// DataURLs returns a data URL for each value in the bundle; keyed by media type.
+func (bundle Bundle) DataURLs() (map[string]string, error) {
+	dataURLs := map[string]string{}
+
+	for mimeType := range bundle {
+		dataURL, err := bundle.DataURL(mimeType)
+		if nil != err {
+			return nil, err
+		}
+
+		dataURLs[mimeType] = dataURL
+	}
+
+	return dataURLs, nil
+}
+
+
+// Set puts the contents of 'parcel' into the bundle, replacing whatever was there; keyed by the
+// media type of 'parcel' (without its parameters), and stored the way a notebook stores that media
+// type.
+//
+// Text (and JSON) contents must be UTF-8, since that is what a notebook is.
+func (bundle Bundle) Set(parcel dataurl.Parcel) error {
+	mimeType, _, err := mime.ParseMediaType(parcel.MediaType())
+	if nil != err {
+		return fmt.Errorf("jupyterurl: bad media type %q: %w", parcel.MediaType(), err)
+	}
+
+	value, err := encode(mimeType, dataurl.UnsafeBytes(parcel))
+	if nil != err {
+		return err
+	}
+
+	bundle[mimeType] = value
+	return nil
+}
+
+
+// SetDataURL parses 'dataURL' (with dataurl.Parse()), and puts its contents into the bundle.
+//
+// See Bundle.Set() for more.
+func (bundle Bundle) SetDataURL(dataURL string) error {
+	parcel, err := dataurl.Parse(dataURL)
+	if nil != err {
+		return err
+	}
+
+	return bundle.Set(parcel)
+}
+
+
+// decode turns the value 'value', for the media type 'mimeType', into a Parcel.
+func decode(mimeType string, value json.RawMessage) (dataurl.Parcel, error) {
+	if isJSON(mimeType) {
+		var buffer bytes.Buffer
+		if err := json.Compact(&buffer, value); nil != err {
+			return nil, fmt.Errorf("jupyterurl: bad JSON value for %q: %w", mimeType, err)
+		}
+
+		return dataurl.NewParcel(mimeType+";charset=utf-8", buffer.Bytes())
+	}
+
+	s, err := joinLines(value)
+	if nil != err {
+		return nil, fmt.Errorf("jupyterurl: bad value for %q: %w", mimeType, err)
+	}
+
+	if isText(mimeType) {
+		return dataurl.NewParcel(mimeType+";charset=utf-8", []byte(s))
+	}
+
+	// Base64 encoded values are sometimes broken into lines.
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\r', '\n':
+			return -1
+		default:
+			return r
+		}
+	}, s)
+
+	data, err := base64.StdEncoding.DecodeString(s)
+	if nil != err {
+		return nil, fmt.Errorf("jupyterurl: bad base64 value for %q: %w", mimeType, err)
+	}
+
+	return dataurl.NewParcel(mimeType, data)
+}
+
+
+// encode turns the contents 'data', of the media type 'mimeType', into a value for a bundle.
+func encode(mimeType string, data []byte) (json.RawMessage, error) {
+	switch {
+	case isJSON(mimeType):
+		var buffer bytes.Buffer
+		if err := json.Compact(&buffer, data); nil != err {
+			return nil, fmt.Errorf("jupyterurl: contents of %q is not JSON: %w", mimeType, err)
+		}
+
+		return json.RawMessage(buffer.Bytes()), nil
+	case isText(mimeType):
+		if !utf8.Valid(data) {
+			return nil, fmt.Errorf("jupyterurl: contents of %q is not UTF-8", mimeType)
+		}
+
+		return marshal(splitLines(string(data)))
+	default:
+		return marshal(base64.StdEncoding.EncodeToString(data))
+	}
+}
+
+
+// marshal is like json.Marshal(), except that "<", ">" and "&" are not escaped. (Which would make
+// HTML unreadable in the .ipynb file.)
+func marshal(value interface{}) (json.RawMessage, error) {
+	var buffer bytes.Buffer
+
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); nil != err {
+		return nil, err
+	}
+
+	return json.RawMessage(bytes.TrimSuffix(buffer.Bytes(), []byte("\n"))), nil
+}
+
+
+// joinLines returns the string that 'value' is. Which is either a JSON string, or a JSON array of
+// strings (which are concatenated).
+func joinLines(value json.RawMessage) (string, error) {
+	value = bytes.TrimSpace(value)
+
+	if bytes.HasPrefix(value, []byte("[")) {
+		var lines []string
+		if err := json.Unmarshal(value, &lines); nil != err {
+			return "", err
+		}
+
+		return strings.Join(lines, ""), nil
+	}
+
+	var s string
+	if err := json.Unmarshal(value, &s); nil != err {
+		return "", err
+	}
+
+	return s, nil
+}
+
+
+// splitLines splits 's' into lines; each (except maybe the last) ending with its "\n". This is
+// how a notebook stores text.
+func splitLines(s string) []string {
+	lines := []string{}
+
+	for "" != s {
+		index := strings.IndexByte(s, '\n')
+		if -1 == index {
+			lines = append(lines, s)
+			break
+		}
+
+		lines = append(lines, s[:index+1])
+		s = s[index+1:]
+	}
+
+	return lines
+}
+
+
+// isJSON returns whether a value for the media type 'mimeType' is stored as a JSON value.
+func isJSON(mimeType string) bool {
+	return "application/json" == mimeType ||
+		(strings.HasPrefix(mimeType, "application/") && strings.HasSuffix(mimeType, "+json"))
+}
+
+
+// isText returns whether a value for the media type 'mimeType' is stored as text (rather than
+// base64 encoded).
+func isText(mimeType string) bool {
+	switch mimeType {
+	case "application/javascript", "image/svg+xml":
+		return true
+	default:
+		return strings.HasPrefix(mimeType, "text/")
+	}
+}
@@ -0,0 +1,161 @@
+package jupyterurl
+
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+
+func TestBundleDataURL(t *testing.T) {
+
+	bundle := Bundle{
+		"image/png":        json.RawMessage(`"iVBORw0K\nGgo=\n"`),
+		"text/html":        json.RawMessage(`["<b>Hello</b>\n", "world!"]`),
+		"text/plain":       json.RawMessage(`"Hello world!"`),
+		"image/svg+xml":    json.RawMessage(`["<svg/>"]`),
+		"application/json": json.RawMessage(`{ "a": [1, 2] }`),
+		"application/pdf":  json.RawMessage(`["JVBE", "Rg=="]`),
+	}
+
+	tests := []struct{
+		MediaType string
+		Expected  string
+	}{
+		{
+			MediaType: "image/png",
+			Expected:  `data:image/png;base64,iVBORw0KGgo=`,
+		},
+		{
+			MediaType: "text/html",
+			Expected:  `data:text/html;charset=utf-8,%3Cb%3EHello%3C/b%3E%0Aworld!`,
+		},
+		{
+			MediaType: "TEXT/Plain",
+			Expected:  `data:;charset=utf-8,Hello%20world!`,
+		},
+		{
+			MediaType: "image/svg+xml",
+			Expected:  `data:image/svg+xml;charset=utf-8,%3Csvg/%3E`,
+		},
+		{
+			MediaType: "application/json",
+			Expected:  `data:application/json;charset=utf-8;base64,eyJhIjpbMSwyXX0=`,
+		},
+		{
+			MediaType: "application/pdf",
+			Expected:  `data:application/pdf;base64,JVBERg==`,
+		},
+		{
+			MediaType: "Image/PNG; name=\"dot.png\"",
+			Expected:  `data:image/png;base64,iVBORw0KGgo=`,
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		actual, err := bundle.DataURL(test.MediaType)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if expected := test.Expected; expected != actual {
+			t.Errorf("For test #%d, expected %q, but actually got %q.", testNumber, expected, actual)
+			continue
+		}
+	}
+
+	dataURLs, err := bundle.DataURLs()
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+	if expected, actual := len(bundle), len(dataURLs); expected != actual {
+		t.Errorf("Expected %d data URLs, but actually got %d.", expected, actual)
+	}
+
+	if _, err := bundle.DataURL("image/jpeg"); nil == err {
+		t.Errorf("Expected an error for a media type not in the bundle, but did not actually get one.")
+	}
+	if _, err := bundle.DataURL("image"); nil == err {
+		t.Errorf("Expected an error for a bad media type, but did not actually get one.")
+	}
+}
+
+
+func TestBundleSetDataURL(t *testing.T) {
+
+	tests := []struct{
+		DataURL           string
+		ExpectedMediaType string
+		ExpectedValue     string
+	}{
+		{
+			DataURL:           `data:image/png;base64,iVBORw0KGgo=`,
+			ExpectedMediaType: "image/png",
+			ExpectedValue:     `"iVBORw0KGgo="`,
+		},
+		{
+			DataURL:           `data:text/html;charset=utf-8,%3Cb%3EHello%3C/b%3E%0Aworld!%0A`,
+			ExpectedMediaType: "text/html",
+			ExpectedValue:     `["<b>Hello</b>\n","world!\n"]`,
+		},
+		{
+			DataURL:           `data:,`,
+			ExpectedMediaType: "text/plain",
+			ExpectedValue:     `[]`,
+		},
+		{
+			DataURL:           `data:Application/JSON,%7B%20%22a%22:%201%20%7D`,
+			ExpectedMediaType: "application/json",
+			ExpectedValue:     `{"a":1}`,
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		bundle := Bundle{}
+
+		if err := bundle.SetDataURL(test.DataURL); nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if expected, actual := 1, len(bundle); expected != actual {
+			t.Errorf("For test #%d, expected %d entry in the bundle, but actually got %d.", testNumber, expected, actual)
+			continue
+		}
+
+		if expected, actual := test.ExpectedValue, string(bundle[test.ExpectedMediaType]); expected != actual {
+			t.Errorf("For test #%d, expected %s, but actually got %s.", testNumber, expected, actual)
+			continue
+		}
+
+		parcel, err := bundle.Parcel(test.ExpectedMediaType)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+		if 0 == testNumber {
+			if expected, actual := "\x89PNG\r\n\x1a\n", parcel.String(); expected != actual {
+				t.Errorf("For test #%d, expected %q, but actually got %q.", testNumber, expected, actual)
+			}
+		}
+	}
+}
+
+
+func TestBundleSetErrors(t *testing.T) {
+
+	dataURLs := []string{
+		`not a data URL`,
+		`data:application/json,%7B`,
+		`data:text/plain;base64,/w==`,
+	}
+
+	for testNumber, dataURL := range dataURLs {
+		if err := (Bundle{}).SetDataURL(dataURL); nil == err {
+			t.Errorf("For test #%d, expected an error, but did not actually get one.", testNumber)
+		}
+	}
+}
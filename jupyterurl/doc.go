@@ -0,0 +1,39 @@
+/*
+Package jupyterurl converts between the MIME bundles of Jupyter notebook (.ipynb) outputs and data URLs.
+
+A code cell's display_data and execute_result outputs have a "data" member, which is a MIME bundle;
+mapping media types to values. How each value is stored depends on its media type (this follows
+what nbformat does):
+
+	JSON (ex: "application/json", "application/vnd.vegalite.v5+json") — as a JSON value (not a string)
+	text (ex: "text/html", "text/plain", "image/svg+xml", "application/javascript") — as a string, or an array of lines
+	everything else (ex: "image/png", "application/pdf") — as a base64 encoded string
+
+Example Usage
+
+	outputs, err := jupyterurl.Outputs(file)
+	if nil != err {
+		//@TODO
+	}
+
+	for _, output := range outputs {
+		dataURL, err := output.Data.DataURL("image/png")
+		if nil != err {
+			//@TODO
+		}
+
+		fmt.Printf("<img src=%q>\n", dataURL)
+	}
+
+Another Example Usage
+
+	bundle := jupyterurl.Bundle{}
+
+	err := bundle.SetDataURL("data:image/png;base64,iVBORw0KGgo...")
+	if nil != err {
+		//@TODO
+	}
+
+	// bundle["image/png"] is now "iVBORw0KGgo..."
+*/
+package jupyterurl
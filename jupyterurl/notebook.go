@@ -0,0 +1,75 @@
+package jupyterurl
+
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+
+const (
+	// OutputTypeDisplayData is the "output_type" of an output from display().
+	OutputTypeDisplayData = "display_data"
+
+	// OutputTypeExecuteResult is the "output_type" of the output that is the result of a code cell.
+	OutputTypeExecuteResult = "execute_result"
+)
+
+
+// Output is a display_data or execute_result output, of a code cell of a notebook.
+type Output struct {
+	// Cell is the index of the cell (in the notebook's "cells") that the output is from.
+	Cell int
+
+	// Index is the index of the output (in the cell's "outputs").
+	Index int
+
+	// OutputType is either OutputTypeDisplayData or OutputTypeExecuteResult.
+	OutputType string
+
+	Data Bundle
+}
+
+
+// Outputs reads a notebook (an .ipynb file) from 'r', and returns its display_data and execute_result
+// outputs; in the order they are in the notebook. (Other outputs, such as stream and error outputs,
+// do not have MIME bundles; and are skipped.)
+//
+// Only version 4 of the notebook format is supported. (Which is what Jupyter has written since 2015.)
+func Outputs(r io.Reader) ([]Output, error) {
+	var notebook struct {
+		NBFormat int `json:"nbformat"`
+		Cells    []struct {
+			Outputs []struct {
+				OutputType string `json:"output_type"`
+				Data       Bundle `json:"data"`
+			} `json:"outputs"`
+		} `json:"cells"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&notebook); nil != err {
+		return nil, fmt.Errorf("jupyterurl: could not read notebook: %w", err)
+	}
+
+	if 4 != notebook.NBFormat {
+		return nil, fmt.Errorf("jupyterurl: notebook format version %d is not supported", notebook.NBFormat)
+	}
+
+	var outputs []Output
+	for cellIndex, cell := range notebook.Cells {
+		for outputIndex, output := range cell.Outputs {
+			switch output.OutputType {
+			case OutputTypeDisplayData, OutputTypeExecuteResult:
+				outputs = append(outputs, Output{
+					Cell:       cellIndex,
+					Index:      outputIndex,
+					OutputType: output.OutputType,
+					Data:       output.Data,
+				})
+			}
+		}
+	}
+
+	return outputs, nil
+}
@@ -0,0 +1,89 @@
+package jupyterurl
+
+
+import (
+	"strings"
+	"testing"
+)
+
+
+const notebook = `{
+ "cells": [
+  {
+   "cell_type": "markdown",
+   "metadata": {},
+   "source": ["# Title"]
+  },
+  {
+   "cell_type": "code",
+   "execution_count": 1,
+   "metadata": {},
+   "outputs": [
+    {"name": "stdout", "output_type": "stream", "text": ["hi\n"]},
+    {"output_type": "display_data", "metadata": {}, "data": {"image/png": "iVBORw0KGgo=\n", "text/plain": ["<Figure>"]}},
+    {"output_type": "execute_result", "execution_count": 1, "metadata": {}, "data": {"text/plain": ["42"]}}
+   ],
+   "source": ["print('hi')"]
+  }
+ ],
+ "metadata": {},
+ "nbformat": 4,
+ "nbformat_minor": 5
+}`
+
+
+func TestOutputs(t *testing.T) {
+	outputs, err := Outputs(strings.NewReader(notebook))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if expected, actual := 2, len(outputs); expected != actual {
+		t.Fatalf("Expected %d outputs, but actually got %d.", expected, actual)
+	}
+
+	{
+		output := outputs[0]
+
+		if expected, actual := 1, output.Cell; expected != actual {
+			t.Errorf("Expected cell %d, but actually got %d.", expected, actual)
+		}
+		if expected, actual := 1, output.Index; expected != actual {
+			t.Errorf("Expected index %d, but actually got %d.", expected, actual)
+		}
+		if expected, actual := OutputTypeDisplayData, output.OutputType; expected != actual {
+			t.Errorf("Expected output type %q, but actually got %q.", expected, actual)
+		}
+
+		dataURL, err := output.Data.DataURL("image/png")
+		if nil != err {
+			t.Fatalf("Did not expect an error, but actually got one: %v", err)
+		}
+		if expected, actual := `data:image/png;base64,iVBORw0KGgo=`, dataURL; expected != actual {
+			t.Errorf("Expected %q, but actually got %q.", expected, actual)
+		}
+	}
+
+	{
+		output := outputs[1]
+
+		if expected, actual := OutputTypeExecuteResult, output.OutputType; expected != actual {
+			t.Errorf("Expected output type %q, but actually got %q.", expected, actual)
+		}
+
+		dataURL, err := output.Data.DataURL("text/plain")
+		if nil != err {
+			t.Fatalf("Did not expect an error, but actually got one: %v", err)
+		}
+		if expected, actual := `data:;charset=utf-8,42`, dataURL; expected != actual {
+			t.Errorf("Expected %q, but actually got %q.", expected, actual)
+		}
+	}
+}
+
+
+func TestOutputsVersion(t *testing.T) {
+	if _, err := Outputs(strings.NewReader(`{"nbformat": 3, "worksheets": []}`)); nil == err {
+		t.Errorf("Expected an error, but did not actually get one.")
+	}
+}
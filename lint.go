@@ -0,0 +1,467 @@
+package dataurl
+
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+
+// Severity is how serious a Finding (from dataurl.Lint()) is.
+type Severity int
+
+
+const (
+	// SeverityInfo is for something that works, but could be better. (Ex: it could be shorter.)
+	SeverityInfo Severity = iota
+
+	// SeverityWarning is for something that might not work; depending on where the data URL is used.
+	SeverityWarning
+
+	// SeverityError is for something that does not work.
+	SeverityError
+)
+
+
+// String returns the name of the severity.
+func (severity Severity) String() string {
+	switch severity {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+
+// The IDs of the rules that dataurl.Lint() checks.
+const (
+	RuleInvalid           = "invalid"            // Not a valid data URL.
+	RuleBase64Text        = "base64-text"        // ASCII text that is base64 encoded, but would be shorter percent encoded.
+	RuleRedundantCharset  = "redundant-charset"  // "charset=US-ASCII", which a data URL implies by default.
+	RuleMediaTypeCase     = "media-type-case"    // A media type that is not lower-case.
+	RuleMediaTypeAlias    = "media-type-alias"   // A media type that is an alias (ex: "image/jpg" for "image/jpeg").
+	RuleUnnecessaryEscape = "unnecessary-escape" // A percent encoded byte that did not need to be. (Ex: "%41" for "A".)
+	RuleUnsafeCharacter   = "unsafe-character"   // A character that breaks the data URL in common places (ex: HTML, CSS, Markdown).
+	RuleTooLarge          = "too-large"          // A data URL longer than LintConfig.MaxLength.
+	RuleTypeMismatch      = "type-mismatch"      // Contents that do not look like the declared media type.
+)
+
+
+// DefaultLintMaxLength is the default for LintConfig.MaxLength. It is the longest data URL
+// that some older browsers accept.
+const DefaultLintMaxLength = 32 * 1024
+
+
+// Finding is a problem found by dataurl.Lint().
+type Finding struct {
+	Rule     string   // One of the Rule… constants. (Ex: dataurl.RuleRedundantCharset.)
+	Severity Severity
+	Message  string
+
+	// Start and End are the byte offsets of the problem in the data URL that was linted.
+	Start int
+	End   int
+
+	// Fix is the suggested fix; or nil if there isn't one.
+	Fix *Fix
+}
+
+
+// Fix is a suggested fix for a Finding. It replaces the bytes from Start to End, of the data URL
+// that was linted, with Replacement.
+type Fix struct {
+	Start       int
+	End         int
+	Replacement string
+}
+
+
+// String returns the finding, as a human would write it.
+//
+// For example:
+//
+//	5-21 info redundant-charset: "charset=US-ASCII" is implied by default
+func (finding Finding) String() string {
+	return fmt.Sprintf("%d-%d %s %s: %s", finding.Start, finding.End, finding.Severity, finding.Rule, finding.Message)
+}
+
+
+// LintConfig is used to configure LintConfig.Lint().
+type LintConfig struct {
+	// MaxLength is the longest (in bytes) a data URL may be without a RuleTooLarge finding.
+	// If zero, then DefaultLintMaxLength is used. If negative, then there is no limit.
+	MaxLength int
+}
+
+
+// Lint checks the data URL 'dataURL' for problems, using the default LintConfig.
+//
+// See LintConfig.Lint() for more.
+func Lint(dataURL string) []Finding {
+	return LintConfig{}.Lint(dataURL)
+}
+
+
+// Lint checks the data URL 'dataURL' for problems; and returns what it found, ordered by
+// where in 'dataURL' each was found.
+//
+// If 'dataURL' cannot be parsed, then just a single RuleInvalid finding is returned.
+//
+// Example usage:
+//
+//	for _, finding := range dataurl.Lint("data:IMAGE/JPG;charset=US-ASCII;base64,SGVsbG8=") {
+//		fmt.Println(finding)
+//	}
+func (config LintConfig) Lint(dataURL string) []Finding {
+	parcel, err := parse(dataURL)
+	if nil != err {
+		return []Finding{{
+			Rule:     RuleInvalid,
+			Severity: SeverityError,
+			Message:  err.Error(),
+			Start:    0,
+			End:      len(dataURL),
+		}}
+	}
+
+	index, base64Encoded := splitDataURL(dataURL)
+	payloadStart := index + len(comma)
+	if base64Encoded {
+		payloadStart = index + len(semicolonBase64Comma)
+	}
+
+	var findings []Finding
+
+	findings = append(findings, lintMediaType(dataURL, len(dataColon), index)...)
+	findings = append(findings, lintUnsafeCharacters(dataURL, len(dataColon), index, false)...)
+
+	if base64Encoded {
+		findings = append(findings, lintBase64Text(parcel, dataURL, index)...)
+	} else {
+		findings = append(findings, lintUnnecessaryEscapes(dataURL, payloadStart)...)
+		findings = append(findings, lintUnsafeCharacters(dataURL, payloadStart, len(dataURL), true)...)
+	}
+
+	findings = append(findings, lintTypeMismatch(parcel, dataURL, len(dataColon), index)...)
+
+	maxLength := config.MaxLength
+	if 0 == maxLength {
+		maxLength = DefaultLintMaxLength
+	}
+	if 0 < maxLength && maxLength < len(dataURL) {
+		findings = append(findings, Finding{
+			Rule:     RuleTooLarge,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("data URL is %d bytes, which is more than %d bytes", len(dataURL), maxLength),
+			Start:    0,
+			End:      len(dataURL),
+		})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Start < findings[j].Start
+	})
+
+	return findings
+}
+
+
+// mediaTypeSegment is the type (or a parameter) of the media type in a data URL, and where it is.
+type mediaTypeSegment struct {
+	Start int
+	End   int
+	Text  string
+}
+
+
+// splitMediaType splits the media type of 'dataURL' (which is from 'start' to 'end') into its
+// type and its parameters. The type is always the first segment (even if it is empty).
+//
+// Semicolons inside of quoted parameter values do not split.
+func splitMediaType(dataURL string, start int, end int) []mediaTypeSegment {
+	var segments []mediaTypeSegment
+
+	segmentStart := start
+	quoted := false
+	for i := start; i < end; i++ {
+		switch dataURL[i] {
+		case '"':
+			quoted = !quoted
+		case '\\':
+			if quoted {
+				i++
+			}
+		case ';':
+			if !quoted {
+				segments = append(segments, mediaTypeSegment{segmentStart, i, dataURL[segmentStart:i]})
+				segmentStart = i + 1
+			}
+		}
+	}
+	segments = append(segments, mediaTypeSegment{segmentStart, end, dataURL[segmentStart:end]})
+
+	return segments
+}
+
+
+func lintMediaType(dataURL string, start int, end int) []Finding {
+	var findings []Finding
+
+	segments := splitMediaType(dataURL, start, end)
+
+	typeSegment := segments[0]
+	if "" != typeSegment.Text {
+		lowered := strings.ToLower(strings.TrimSpace(typeSegment.Text))
+
+		if alias, ok := mediaTypeAliases[lowered]; ok {
+			findings = append(findings, Finding{
+				Rule:     RuleMediaTypeAlias,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("%q is an alias of %q", typeSegment.Text, alias),
+				Start:    typeSegment.Start,
+				End:      typeSegment.End,
+				Fix:      &Fix{typeSegment.Start, typeSegment.End, alias},
+			})
+		} else if lowered != typeSegment.Text {
+			findings = append(findings, Finding{
+				Rule:     RuleMediaTypeCase,
+				Severity: SeverityInfo,
+				Message:  fmt.Sprintf("media type %q is usually written lower-case", typeSegment.Text),
+				Start:    typeSegment.Start,
+				End:      typeSegment.End,
+				Fix:      &Fix{typeSegment.Start, typeSegment.End, lowered},
+			})
+		}
+	}
+
+	for _, segment := range segments[1:] {
+		name, value, found := strings.Cut(segment.Text, "=")
+		if !found || !strings.EqualFold("charset", strings.TrimSpace(name)) {
+			continue
+		}
+
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		if !strings.EqualFold("US-ASCII", value) {
+			continue
+		}
+
+		// Remove the semicolon before the parameter too.
+		findings = append(findings, Finding{
+			Rule:     RuleRedundantCharset,
+			Severity: SeverityInfo,
+			Message:  fmt.Sprintf("%q is implied by default", segment.Text),
+			Start:    segment.Start,
+			End:      segment.End,
+			Fix:      &Fix{segment.Start-1, segment.End, ""},
+		})
+	}
+
+	return findings
+}
+
+
+// lintUnsafeCharacters reports each character (from 'start' to 'end' in 'dataURL') that is not
+// allowed in a URL; and so breaks the data URL in common places. For example, a space ends an
+// unquoted HTML attribute, a Markdown link destination, and an unquoted CSS url(). And a "#"
+// starts a fragment; so a browser drops everything after it.
+//
+// It also reports the characters that are allowed in a URL, but still break it in those places.
+// See breaksInContext().
+//
+// Only characters in the (percent encoded) contents can be fixed, by percent encoding them.
+// (dataurl.Parse() does not percent decode the media type.)
+func lintUnsafeCharacters(dataURL string, start int, end int, fixable bool) []Finding {
+	var findings []Finding
+
+	for i := start; i < end; {
+		r, size := utf8.DecodeRuneInString(dataURL[i:])
+
+		unsafe := false
+		severity := SeverityWarning
+		switch {
+		case '#' == r:
+			unsafe = true
+			severity = SeverityError
+		case r <= ' ', 0x7F == r, utf8.RuneSelf <= r:
+			unsafe = true
+		case strings.ContainsRune("\"<>\\^`{|}", r):
+			unsafe = true
+		case r < utf8.RuneSelf && breaksInContext(byte(r)):
+			unsafe = true
+		}
+
+		if unsafe {
+			finding := Finding{
+				Rule:     RuleUnsafeCharacter,
+				Severity: severity,
+				Message:  fmt.Sprintf("%q is not allowed in a URL, and should be percent encoded", dataURL[i:i+size]),
+				Start:    i,
+				End:      i + size,
+			}
+			switch {
+			case '#' == r:
+				finding.Message = `"#" starts a URL fragment, so everything after it is dropped; it should be percent encoded`
+			case r < utf8.RuneSelf && breaksInContext(byte(r)):
+				finding.Message = fmt.Sprintf("%q is allowed in a URL, but breaks Markdown links, unquoted CSS url()s or single quoted HTML attributes; it should be percent encoded", dataURL[i:i+size])
+			}
+			if fixable {
+				var replacement strings.Builder
+				for _, b := range []byte(dataURL[i:i+size]) {
+					fmt.Fprintf(&replacement, "%%%02X", b)
+				}
+				finding.Fix = &Fix{i, i + size, replacement.String()}
+			}
+			findings = append(findings, finding)
+		}
+
+		i += size
+	}
+
+	return findings
+}
+
+
+// lintUnnecessaryEscapes reports each percent encoded byte (in the contents, which start at
+// 'start') that did not need to be percent encoded.
+//
+// The bytes that breaksInContext() is true for do need to be; so those are not reported.
+func lintUnnecessaryEscapes(dataURL string, start int) []Finding {
+	var findings []Finding
+
+	for i := start; i+2 < len(dataURL); i++ {
+		if '%' != dataURL[i] || !isHex(dataURL[i+1]) || !isHex(dataURL[i+2]) {
+			continue
+		}
+
+		b := unhex(dataURL[i+1])<<4 | unhex(dataURL[i+2])
+		if !shouldPercentEncode(b) && !breaksInContext(b) {
+			findings = append(findings, Finding{
+				Rule:     RuleUnnecessaryEscape,
+				Severity: SeverityInfo,
+				Message:  fmt.Sprintf("%q does not need to be percent encoded; it can just be %q", dataURL[i:i+3], string(rune(b))),
+				Start:    i,
+				End:      i + 3,
+				Fix:      &Fix{i, i + 3, string(rune(b))},
+			})
+		}
+
+		i += 2
+	}
+
+	return findings
+}
+
+
+// breaksInContext returns whether the byte 'b' (which is allowed in a URL) still breaks a data URL
+// in a common place, unless it is percent encoded. A "'" ends a single quoted HTML attribute (and a
+// single quoted CSS string); and a "(" or ")" breaks a Markdown link destination and an unquoted
+// CSS url().
+func breaksInContext(b byte) bool {
+	switch b {
+	case '\'', '(', ')':
+		return true
+	default:
+		return false
+	}
+}
+
+
+// lintBase64Text reports base64 encoded contents that are ASCII text, if percent encoding them
+// would not be longer. The ";base64," is from 'index' (in 'dataURL') to the contents; and the
+// contents go to the end of 'dataURL'.
+func lintBase64Text(parcel *internalParcel, dataURL string, index int) []Finding {
+	data := UnsafeBytes(parcel)
+	if 0 == len(data) {
+		return nil
+	}
+
+	for _, b := range data {
+		if (b < ' ' && '\t' != b && '\n' != b && '\r' != b) || 0x7F <= b {
+			return nil
+		}
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString(comma)
+	for _, b := range data {
+		// So that the fix does not cause a RuleUnsafeCharacter finding.
+		if breaksInContext(b) {
+			fmt.Fprintf(&buffer, "%%%02X", b)
+			continue
+		}
+		percentEncode(&buffer, []byte{b})
+	}
+
+	base64Len, _ := encodedLen("", data, EncodingBase64)
+	percentLen   := len(dataColon) + buffer.Len()
+	if base64Len < percentLen {
+		return nil
+	}
+
+	return []Finding{{
+		Rule:     RuleBase64Text,
+		Severity: SeverityInfo,
+		Message:  fmt.Sprintf("contents are ASCII text; percent encoding them would be %d bytes shorter", base64Len-percentLen),
+		Start:    index,
+		End:      len(dataURL),
+		Fix:      &Fix{index, len(dataURL), buffer.String()},
+	}}
+}
+
+
+// lintTypeMismatch reports contents that (when sniffed) do not look like the declared media type.
+//
+// Only sniffed types that are specific enough to be sure of are reported. (Ex: an image that is
+// declared as some other type of image.) Text (which is sniffed as "text/plain", "text/html",
+// "text/xml", etc) and unknown binary data (which is sniffed as "application/octet-stream")
+// could be a lot of things; so those are not reported.
+func lintTypeMismatch(parcel *internalParcel, dataURL string, start int, end int) []Finding {
+	data := UnsafeBytes(parcel)
+	if 0 == len(data) {
+		return nil
+	}
+
+	declared, _, err := mime.ParseMediaType(parcel.mediaType)
+	if nil != err {
+		return nil
+	}
+	if alias, ok := mediaTypeAliases[declared]; ok {
+		declared = alias
+	}
+	if "application/octet-stream" == declared {
+		return nil
+	}
+
+	sniffed := essenceOf(http.DetectContentType(data))
+	if alias, ok := mediaTypeAliases[sniffed]; ok {
+		sniffed = alias
+	}
+	if strings.HasPrefix(sniffed, "text/") || "application/octet-stream" == sniffed || sniffed == declared {
+		return nil
+	}
+
+	typeSegment := splitMediaType(dataURL, start, end)[0]
+
+	finding := Finding{
+		Rule:     RuleTypeMismatch,
+		Severity: SeverityWarning,
+		Message:  fmt.Sprintf("declared media type is %q, but the contents look like %q", declared, sniffed),
+		Start:    typeSegment.Start,
+		End:      typeSegment.End,
+	}
+	if "" != typeSegment.Text {
+		finding.Fix = &Fix{typeSegment.Start, typeSegment.End, sniffed}
+	}
+
+	return []Finding{finding}
+}
@@ -0,0 +1,180 @@
+package dataurl
+
+
+import (
+	"strings"
+	"testing"
+)
+
+
+// applyFixes applies the fixes of 'findings' to 'dataURL'. Findings whose fixes overlap
+// an earlier fix are skipped.
+func applyFixes(dataURL string, findings []Finding) string {
+	var builder strings.Builder
+
+	last := 0
+	for _, finding := range findings {
+		fix := finding.Fix
+		if nil == fix || fix.Start < last {
+			continue
+		}
+
+		builder.WriteString(dataURL[last:fix.Start])
+		builder.WriteString(fix.Replacement)
+		last = fix.End
+	}
+	builder.WriteString(dataURL[last:])
+
+	return builder.String()
+}
+
+
+func TestLint(t *testing.T) {
+
+	tests := []struct{
+		DataURL       string
+		ExpectedRules []string
+		ExpectedFixed string
+	}{
+		{
+			DataURL:       `data:,Hello%20world!`,
+			ExpectedRules: nil,
+			ExpectedFixed: `data:,Hello%20world!`,
+		},
+		{
+			DataURL:       `data:text/plain;charset=US-ASCII,Hello`,
+			ExpectedRules: []string{RuleRedundantCharset},
+			ExpectedFixed: `data:text/plain,Hello`,
+		},
+		{
+			DataURL:       `data:TEXT/Plain,Hello`,
+			ExpectedRules: []string{RuleMediaTypeCase},
+			ExpectedFixed: `data:text/plain,Hello`,
+		},
+		{
+			DataURL:       `data:image/jpg;base64,/9j/4AAQSkZJRgABAQ==`,
+			ExpectedRules: []string{RuleMediaTypeAlias},
+			ExpectedFixed: `data:image/jpeg;base64,/9j/4AAQSkZJRgABAQ==`,
+		},
+		{
+			DataURL:       `data:,%48ello`,
+			ExpectedRules: []string{RuleUnnecessaryEscape},
+			ExpectedFixed: `data:,Hello`,
+		},
+		{
+			DataURL:       `data:,a%2Bb%25c`,
+			ExpectedRules: nil,
+			ExpectedFixed: `data:,a%2Bb%25c`,
+		},
+		{
+			DataURL:       `data:text/plain,a%29b%27c%28`,
+			ExpectedRules: nil,
+			ExpectedFixed: `data:text/plain,a%29b%27c%28`,
+		},
+		{
+			DataURL:       `data:text/plain,a%29b%27c%28%2Ad`,
+			ExpectedRules: []string{RuleUnnecessaryEscape},
+			ExpectedFixed: `data:text/plain,a%29b%27c%28*d`,
+		},
+		{
+			DataURL:       `data:text/plain,a)b'c(`,
+			ExpectedRules: []string{RuleUnsafeCharacter, RuleUnsafeCharacter, RuleUnsafeCharacter},
+			ExpectedFixed: `data:text/plain,a%29b%27c%28`,
+		},
+		{
+			DataURL:       `data:text/plain;base64,KGl0J3Mp`,
+			ExpectedRules: []string{RuleBase64Text},
+			ExpectedFixed: `data:text/plain,%28it%27s%29`,
+		},
+		{
+			DataURL:       `data:,say "hi"#1`,
+			ExpectedRules: []string{RuleUnsafeCharacter, RuleUnsafeCharacter, RuleUnsafeCharacter, RuleUnsafeCharacter},
+			ExpectedFixed: `data:,say%20%22hi%22%231`,
+		},
+		{
+			DataURL:       "data:;charset=utf-8,café",
+			ExpectedRules: []string{RuleUnsafeCharacter},
+			ExpectedFixed: `data:;charset=utf-8,caf%C3%A9`,
+		},
+		{
+			DataURL:       `data:text/plain;base64,SGVsbG8sV29ybGQh`,
+			ExpectedRules: []string{RuleBase64Text},
+			ExpectedFixed: `data:text/plain,Hello,World!`,
+		},
+		{
+			DataURL:       `data:image/gif;base64,iVBORw0KGgoAAAANSUhEUg==`,
+			ExpectedRules: []string{RuleTypeMismatch},
+			ExpectedFixed: `data:image/png;base64,iVBORw0KGgoAAAANSUhEUg==`,
+		},
+		{
+			DataURL:       `data:image/svg+xml,%3Csvg/%3E`,
+			ExpectedRules: nil,
+			ExpectedFixed: `data:image/svg+xml,%3Csvg/%3E`,
+		},
+		{
+			DataURL:       `http://example.com/`,
+			ExpectedRules: []string{RuleInvalid},
+			ExpectedFixed: `http://example.com/`,
+		},
+		{
+			DataURL:       `data:bad media type,x`,
+			ExpectedRules: []string{RuleInvalid},
+			ExpectedFixed: `data:bad media type,x`,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		findings := Lint(test.DataURL)
+
+		var actualRules []string
+		for _, finding := range findings {
+			actualRules = append(actualRules, finding.Rule)
+
+			if finding.Start < 0 || finding.End < finding.Start || len(test.DataURL) < finding.End {
+				t.Errorf("For test #%d, bad byte range in finding: %v", testNumber, finding)
+			}
+		}
+
+		if expected, actual := strings.Join(test.ExpectedRules, ","), strings.Join(actualRules, ","); expected != actual {
+			t.Errorf("For test #%d, expected rules %q, but actually got %q.\nData URL: %q\nFindings: %v", testNumber, expected, actual, test.DataURL, findings)
+			continue
+		}
+
+		fixed := applyFixes(test.DataURL, findings)
+		if expected, actual := test.ExpectedFixed, fixed; expected != actual {
+			t.Errorf("For test #%d, expected fixed data URL to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+
+		// Fixing must not change what the data URL means.
+		if nil != test.ExpectedRules && RuleInvalid != test.ExpectedRules[0] && RuleTypeMismatch != test.ExpectedRules[0] && RuleMediaTypeAlias != test.ExpectedRules[0] {
+			equivalent, err := EquivalentURLs(test.DataURL, fixed)
+			if nil != err {
+				t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+				continue
+			}
+			if !equivalent {
+				t.Errorf("For test #%d, expected the fixed data URL %q to be equivalent to %q, but it was not.", testNumber, fixed, test.DataURL)
+				continue
+			}
+		}
+	}
+}
+
+
+func TestLintMaxLength(t *testing.T) {
+	dataURL := "data:," + strings.Repeat("a", 100)
+
+	if findings := (LintConfig{MaxLength: 100}).Lint(dataURL); 1 != len(findings) || RuleTooLarge != findings[0].Rule {
+		t.Errorf("Expected a single %q finding, but actually got: %v", RuleTooLarge, findings)
+	}
+
+	if findings := (LintConfig{MaxLength: 200}).Lint(dataURL); 0 != len(findings) {
+		t.Errorf("Did not expect any findings, but actually got: %v", findings)
+	}
+
+	if findings := (LintConfig{MaxLength: -1}).Lint("data:," + strings.Repeat("a", 2*DefaultLintMaxLength)); 0 != len(findings) {
+		t.Errorf("Did not expect any findings, but actually got: %v", findings)
+	}
+}
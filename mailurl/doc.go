@@ -0,0 +1,41 @@
+/*
+Package mailurl converts between data URLs and MIME parts (as used by email); using mime/multipart and net/mail.
+
+A MIME part (or a net/mail message) is turned into a dataurl.Parcel using its Content-Type and
+Content-Transfer-Encoding headers. (base64, quoted-printable, 7bit, 8bit and binary are supported.)
+
+A dataurl.Parcel is written out as a MIME part with Content-Type, Content-Transfer-Encoding and
+Content-Disposition headers, and a base64 encoded body wrapped at 76 characters per line. A file
+name that is not ASCII is encoded as in RFC 2231.
+
+Since many email clients block data URLs in images, mailurl.ToCID() rewrites the data URLs in
+the <img> tags of an HTML email into cid: URLs; returning the images as attachments for a
+multipart/related email. And mailurl.FromCID() does the opposite; so that the HTML can be
+shown by itself.
+
+Example Usage
+
+	reader := multipart.NewReader(body, boundary)
+
+	part, err := reader.NextPart()
+	if nil != err {
+		//@TODO
+	}
+
+	parcel, err := mailurl.FromPart(part)
+	if nil != err {
+		//@TODO
+	}
+
+	dataURL, err := dataurl.Encode(parcel.MediaType(), parcel.Bytes(), dataurl.EncodingBase64)
+
+Another Example Usage
+
+	writer := multipart.NewWriter(&buffer)
+
+	err := mailurl.WritePart(writer, parcel, "résumé.pdf")
+	if nil != err {
+		//@TODO
+	}
+*/
+package mailurl
@@ -0,0 +1,218 @@
+package mailurl
+
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+const (
+	// defaultContentType is the Content-Type of a MIME part that does not have one. (See RFC 2045.)
+	defaultContentType = "text/plain; charset=us-ascii"
+
+	// lineLength is the most base64 characters put on a line. (See RFC 2045.)
+	lineLength = 76
+)
+
+
+var (
+	errNilMessage = errors.New("mailurl: nil message")
+	errNilParcel  = errors.New("mailurl: nil parcel")
+	errNilPart    = errors.New("mailurl: nil part")
+)
+
+
+// FromPart returns a dataurl.Parcel with the (decoded) contents and media type of 'part'.
+//
+// Note that multipart.Reader.NextPart() already decodes quoted-printable parts (and removes
+// their Content-Transfer-Encoding header); while multipart.Reader.NextRawPart() does not.
+// FromPart works with either.
+func FromPart(part *multipart.Part) (dataurl.Parcel, error) {
+	if nil == part {
+		return nil, errNilPart
+	}
+
+	return Decode(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+}
+
+
+// FromMessage returns a dataurl.Parcel with the (decoded) contents and media type of 'message'.
+//
+// 'message' must not be a multipart message. (Use mime/multipart to get at its parts; and
+// mailurl.FromPart() on each of them.)
+func FromMessage(message *mail.Message) (dataurl.Parcel, error) {
+	if nil == message {
+		return nil, errNilMessage
+	}
+
+	return Decode(message.Header.Get("Content-Type"), message.Header.Get("Content-Transfer-Encoding"), message.Body)
+}
+
+
+// Decode returns a dataurl.Parcel with the contents of 'body', decoded as specified by
+// 'contentTransferEncoding'; and with the media type 'contentType'.
+//
+// 'contentType' and 'contentTransferEncoding' are the values of the Content-Type and the
+// Content-Transfer-Encoding headers of a MIME part. Either may be the empty string, if the
+// MIME part does not have that header.
+func Decode(contentType string, contentTransferEncoding string, body io.Reader) (dataurl.Parcel, error) {
+	if "" == strings.TrimSpace(contentType) {
+		contentType = defaultContentType
+	}
+
+	mimeType, params, err := mime.ParseMediaType(contentType)
+	if nil != err {
+		return nil, fmt.Errorf("mailurl: bad Content-Type %q: %w", contentType, err)
+	}
+	if strings.HasPrefix(mimeType, "multipart/") {
+		return nil, fmt.Errorf("mailurl: cannot put a %s part into a data URL; only its parts", mimeType)
+	}
+
+	var reader io.Reader
+	switch encoding := strings.ToLower(strings.TrimSpace(contentTransferEncoding)); encoding {
+	case "", "7bit", "8bit", "binary":
+		reader = body
+	case "base64":
+		reader = base64.NewDecoder(base64.StdEncoding, whitespaceRemover{body})
+	case "quoted-printable":
+		reader = quotedprintable.NewReader(body)
+	default:
+		return nil, fmt.Errorf("mailurl: Content-Transfer-Encoding %q is not supported", contentTransferEncoding)
+	}
+
+	data, err := io.ReadAll(reader)
+	if nil != err {
+		return nil, fmt.Errorf("mailurl: could not decode %s part: %w", mimeType, err)
+	}
+
+	return dataurl.NewParcel(mime.FormatMediaType(mimeType, params), data)
+}
+
+
+// Header returns the headers of a MIME part for 'parcel': Content-Type, Content-Transfer-Encoding
+// (always base64), and (if 'filename' is not the empty string) Content-Disposition.
+//
+// A 'filename' that is not ASCII is encoded as in RFC 2231. For example:
+//
+//	Content-Disposition: attachment; filename*=utf-8''r%C3%A9sum%C3%A9.pdf
+func Header(parcel dataurl.Parcel, filename string) (textproto.MIMEHeader, error) {
+	if nil == parcel {
+		return nil, errNilParcel
+	}
+
+	contentType, err := contentTypeOf(parcel)
+	if nil != err {
+		return nil, err
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Transfer-Encoding", "base64")
+
+	if "" != filename {
+		header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	}
+
+	return header, nil
+}
+
+
+// WriteBody writes the contents of 'parcel' to 'w', base64 encoded, with lines (ending with CRLF)
+// of no more than 76 characters.
+func WriteBody(w io.Writer, parcel dataurl.Parcel) error {
+	if nil == parcel {
+		return errNilParcel
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(dataurl.UnsafeBytes(parcel))
+
+	for 0 < len(encoded) {
+		n := min(lineLength, len(encoded))
+
+		if _, err := io.WriteString(w, encoded[:n]); nil != err {
+			return err
+		}
+		if _, err := io.WriteString(w, "\r\n"); nil != err {
+			return err
+		}
+
+		encoded = encoded[n:]
+	}
+
+	return nil
+}
+
+
+// WritePart writes 'parcel' as the next part of 'writer'; with the headers from mailurl.Header(),
+// and the body from mailurl.WriteBody().
+func WritePart(writer *multipart.Writer, parcel dataurl.Parcel, filename string) error {
+	header, err := Header(parcel, filename)
+	if nil != err {
+		return err
+	}
+
+	return writeHeaderAndBody(writer, header, parcel)
+}
+
+
+func writeHeaderAndBody(writer *multipart.Writer, header textproto.MIMEHeader, parcel dataurl.Parcel) error {
+	w, err := writer.CreatePart(header)
+	if nil != err {
+		return err
+	}
+
+	return WriteBody(w, parcel)
+}
+
+
+// contentTypeOf returns the media type of 'parcel', as a Content-Type header.
+//
+// The "charset=US-ASCII" that a data URL implies by default is left out for media types that
+// are not text; since it does not mean anything for them.
+func contentTypeOf(parcel dataurl.Parcel) (string, error) {
+	mimeType, params, err := mime.ParseMediaType(parcel.MediaType())
+	if nil != err {
+		return "", fmt.Errorf("mailurl: bad media type %q: %w", parcel.MediaType(), err)
+	}
+
+	if charset, ok := params["charset"]; ok && !strings.HasPrefix(mimeType, "text/") && strings.EqualFold("US-ASCII", charset) {
+		delete(params, "charset")
+	}
+
+	return mime.FormatMediaType(mimeType, params), nil
+}
+
+
+// whitespaceRemover removes whitespace (such as line breaks) as it is read; which base64 encoded
+// MIME parts can have in them.
+type whitespaceRemover struct {
+	reader io.Reader
+}
+
+
+func (receiver whitespaceRemover) Read(p []byte) (int, error) {
+	n, err := receiver.reader.Read(p)
+
+	kept := 0
+	for _, b := range p[:n] {
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		p[kept] = b
+		kept++
+	}
+
+	return kept, err
+}
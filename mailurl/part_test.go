@@ -0,0 +1,196 @@
+package mailurl
+
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+func TestDecode(t *testing.T) {
+
+	tests := []struct{
+		ContentType             string
+		ContentTransferEncoding string
+		Body                    string
+		ExpectedMediaType       string
+		ExpectedContent         string
+	}{
+		{
+			ContentType:             "",
+			ContentTransferEncoding: "",
+			Body:                    "Hello world!",
+			ExpectedMediaType:       "text/plain; charset=us-ascii",
+			ExpectedContent:         "Hello world!",
+		},
+		{
+			ContentType:             `image/png; name="dot.png"`,
+			ContentTransferEncoding: "base64",
+			Body:                    "iVBORw0KGgoAAAAN\r\nSUhEUgAAAAEAAAAB\r\n",
+			ExpectedMediaType:       `image/png; name=dot.png;charset=US-ASCII`,
+			ExpectedContent:         "\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR\x00\x00\x00\x01\x00\x00\x00\x01",
+		},
+		{
+			ContentType:             "text/plain; charset=utf-8",
+			ContentTransferEncoding: "Quoted-Printable",
+			Body:                    "caf=C3=A9 au lait, a very long line that has been wrapp=\r\ned",
+			ExpectedMediaType:       "text/plain; charset=utf-8",
+			ExpectedContent:         "café au lait, a very long line that has been wrapped",
+		},
+		{
+			ContentType:             "TEXT/HTML",
+			ContentTransferEncoding: "8bit",
+			Body:                    "<p>Hi</p>",
+			ExpectedMediaType:       "text/html;charset=US-ASCII",
+			ExpectedContent:         "<p>Hi</p>",
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		parcel, err := Decode(test.ContentType, test.ContentTransferEncoding, strings.NewReader(test.Body))
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if expected, actual := test.ExpectedMediaType, parcel.MediaType(); expected != actual {
+			t.Errorf("For test #%d, expected media type to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+		if expected, actual := test.ExpectedContent, parcel.String(); expected != actual {
+			t.Errorf("For test #%d, expected content to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+	}
+}
+
+
+func TestDecodeErrors(t *testing.T) {
+
+	tests := []struct{
+		ContentType             string
+		ContentTransferEncoding string
+		Body                    string
+	}{
+		{
+			ContentType:             "multipart/mixed; boundary=x",
+			ContentTransferEncoding: "",
+			Body:                    "",
+		},
+		{
+			ContentType:             "image/png",
+			ContentTransferEncoding: "x-uuencode",
+			Body:                    "",
+		},
+		{
+			ContentType:             "image/png",
+			ContentTransferEncoding: "base64",
+			Body:                    "not base64!",
+		},
+		{
+			ContentType:             "not a content type",
+			ContentTransferEncoding: "",
+			Body:                    "",
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		if _, err := Decode(test.ContentType, test.ContentTransferEncoding, strings.NewReader(test.Body)); nil == err {
+			t.Errorf("For test #%d, expected an error, but did not actually get one.", testNumber)
+			continue
+		}
+	}
+}
+
+
+func TestFromMessage(t *testing.T) {
+	const raw = "From: a@example.com\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"\r\n" +
+		"SGVsbG8sIHdvcmxkIQ==\r\n"
+
+	message, err := mail.ReadMessage(strings.NewReader(raw))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	parcel, err := FromMessage(message)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if expected, actual := "Hello, world!", parcel.String(); expected != actual {
+		t.Errorf("Expected content to be %q, but actually was %q.", expected, actual)
+	}
+}
+
+
+func TestWritePartRoundTrip(t *testing.T) {
+
+	data := bytes.Repeat([]byte("\x00\x01\x02 binary data \xff"), 20)
+
+	parcel, err := dataurl.NewParcel("application/octet-stream", data)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+
+	if err := WritePart(writer, parcel, "résumé.bin"); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+	if err := writer.Close(); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	for _, line := range strings.Split(buffer.String(), "\r\n") {
+		if 76 < len(line) {
+			t.Errorf("Expected no line to be longer than 76 characters, but this one was %d: %q", len(line), line)
+		}
+	}
+
+	if !strings.Contains(buffer.String(), "filename*=utf-8''r%C3%A9sum%C3%A9.bin") {
+		t.Errorf("Expected an RFC 2231 encoded filename, but did not find one in:\n%s", buffer.String())
+	}
+
+	// Use NextRawPart, so that the Content-Transfer-Encoding header is kept.
+	reader := multipart.NewReader(&buffer, writer.Boundary())
+	part, err := reader.NextRawPart()
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if expected, actual := "résumé.bin", part.FileName(); expected != actual {
+		t.Errorf("Expected file name to be %q, but actually was %q.", expected, actual)
+	}
+	if expected, actual := "application/octet-stream", part.Header.Get("Content-Type"); expected != actual {
+		t.Errorf("Expected Content-Type to be %q, but actually was %q.", expected, actual)
+	}
+
+	roundTripped, err := FromPart(part)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if !bytes.Equal(data, roundTripped.Bytes()) {
+		t.Errorf("Expected the contents to round trip, but they did not.\nExpected: %q\nActual:   %q", data, roundTripped.Bytes())
+	}
+	if _, _, err := mime.ParseMediaType(roundTripped.MediaType()); nil != err {
+		t.Errorf("Did not expect an error parsing the media type, but actually got one: %v", err)
+	}
+
+	if _, err := io.ReadAll(part); nil != err {
+		t.Errorf("Did not expect an error, but actually got one: %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package mailurl
+
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"strings"
+
+	"github.com/reiver/go-dataurl"
+	"github.com/reiver/go-dataurl/internal/embedded"
+)
+
+
+// DefaultContentIDDomain is the domain used in the Content-IDs created by mailurl.ToCID(), if
+// it is not given one.
+const DefaultContentIDDomain = "dataurl.invalid"
+
+
+// Attachment is an image (pulled out of HTML by mailurl.ToCID()) that is to be attached
+// to a multipart/related email; and referred to, in the HTML, by its Content-ID.
+type Attachment struct {
+	// ContentID is the Content-ID of the attachment; without the "<" and ">" around it.
+	// In the HTML it is referred to as "cid:" + ContentID.
+	ContentID string
+
+	// Filename is a file name for the attachment; made from ContentID and the media type.
+	Filename string
+
+	Parcel dataurl.Parcel
+}
+
+
+// Header returns the headers of a MIME part for the attachment. These are the same as the
+// headers from mailurl.Header(), except that the Content-Disposition is "inline" and there
+// is a Content-ID.
+func (attachment Attachment) Header() (textproto.MIMEHeader, error) {
+	header, err := Header(attachment.Parcel, "")
+	if nil != err {
+		return nil, err
+	}
+
+	header.Set("Content-ID", "<" + attachment.ContentID + ">")
+
+	disposition := map[string]string{}
+	if "" != attachment.Filename {
+		disposition["filename"] = attachment.Filename
+	}
+	header.Set("Content-Disposition", mime.FormatMediaType("inline", disposition))
+
+	return header, nil
+}
+
+
+// WriteAttachment writes 'attachment' as the next part of 'writer'; which should be the
+// writer of a multipart/related message.
+func WriteAttachment(writer *multipart.Writer, attachment Attachment) error {
+	header, err := attachment.Header()
+	if nil != err {
+		return err
+	}
+
+	return writeHeaderAndBody(writer, header, attachment.Parcel)
+}
+
+
+// ToCID rewrites each <img src="data:..."> in 'htmlBody' to refer to an attachment instead;
+// using a cid: URL. (Many email clients block data URLs in images, but not cid: URLs.)
+//
+// It returns the rewritten HTML, and the attachments. Identical images (same media type and
+// same contents) become a single attachment. (As in a browser, the "data:" scheme is case-insensitive.)
+// An image whose data URL cannot be parsed is left as it is.
+//
+// The Content-IDs are made from a hash of the contents of each image, and 'domain'. If 'domain'
+// is the empty string, then DefaultContentIDDomain is used.
+//
+// Example usage:
+//
+//	rewritten, attachments := mailurl.ToCID(htmlBody, "example.com")
+//
+//	writer := multipart.NewWriter(&buffer)
+//	// Content-Type: multipart/related; boundary=...; type="text/html"
+//
+//	part, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/html; charset=utf-8"}})
+//	//...
+//	io.WriteString(part, rewritten)
+//
+//	for _, attachment := range attachments {
+//		err := mailurl.WriteAttachment(writer, attachment)
+//		//...
+//	}
+func ToCID(htmlBody string, domain string) (string, []Attachment) {
+	if "" == domain {
+		domain = DefaultContentIDDomain
+	}
+
+	var attachments []Attachment
+	var spans []embedded.Span
+
+	for _, match := range embedded.Find([]byte(htmlBody), embedded.SyntaxHTML) {
+		if "img" != match.Element || "src" != match.Attribute || !embedded.HasScheme(match.URL, "data:") {
+			continue
+		}
+
+		parcel, err := embedded.Parse(match.URL)
+		if nil != err {
+			continue
+		}
+
+		var attachment *Attachment
+		taken := false
+		id := contentIDOf(parcel)
+		for i := range attachments {
+			if dataurl.Equal(attachments[i].Parcel, parcel) {
+				attachment = &attachments[i]
+				break
+			}
+			if strings.HasPrefix(attachments[i].ContentID, id) {
+				taken = true
+			}
+		}
+
+		if nil == attachment {
+			// Same contents but a different media type.
+			if taken {
+				id = fmt.Sprintf("%s-%d", id, len(attachments)+1)
+			}
+
+			attachments = append(attachments, Attachment{
+				ContentID: id + "@" + domain,
+				Filename:  id + embedded.ExtensionFor(parcel.MediaType()),
+				Parcel:    parcel,
+			})
+			attachment = &attachments[len(attachments)-1]
+		}
+
+		spans = append(spans, embedded.Span{
+			Start: match.Start,
+			End:   match.End,
+			Text:  match.Escape("cid:" + attachment.ContentID),
+		})
+	}
+
+	return string(embedded.Replace([]byte(htmlBody), spans)), attachments
+}
+
+
+// FromCID does the opposite of mailurl.ToCID(). It rewrites each src (and background) attribute
+// in 'htmlBody' that is a cid: URL into a data URL; so that the HTML can be shown by itself.
+//
+// 'parts' maps Content-IDs to what they refer to. (The Content-IDs may be with, or without, the
+// "<" and ">" around them; as they are in the Content-ID header.) cid: URLs for Content-IDs not
+// in 'parts' are left as they are.
+//
+// Example usage:
+//
+//	parts := map[string]dataurl.Parcel{}
+//	for {
+//		part, err := reader.NextPart()
+//		//...
+//		parcel, err := mailurl.FromPart(part)
+//		//...
+//		parts[part.Header.Get("Content-ID")] = parcel
+//	}
+//
+//	displayable, err := mailurl.FromCID(htmlBody, parts)
+func FromCID(htmlBody string, parts map[string]dataurl.Parcel) (string, error) {
+	normalized := map[string]dataurl.Parcel{}
+	for contentID, parcel := range parts {
+		normalized[embedded.NormalizeContentID(contentID)] = parcel
+	}
+
+	var spans []embedded.Span
+
+	for _, match := range embedded.Find([]byte(htmlBody), embedded.SyntaxHTML) {
+		if ("src" != match.Attribute && "background" != match.Attribute) || !embedded.HasScheme(match.URL, "cid:") {
+			continue
+		}
+
+		// A cid: URL is the Content-ID, percent encoded. (See RFC 2392.)
+		contentID, err := url.PathUnescape(match.URL[len("cid:"):])
+		if nil != err {
+			continue
+		}
+
+		parcel, ok := normalized[embedded.NormalizeContentID(contentID)]
+		if !ok || nil == parcel {
+			continue
+		}
+
+		dataURL, err := dataurl.EncodeShortest(parcel.MediaType(), dataurl.UnsafeBytes(parcel))
+		if nil != err {
+			return "", err
+		}
+
+		spans = append(spans, embedded.Span{
+			Start: match.Start,
+			End:   match.End,
+			Text:  match.Escape(dataURL),
+		})
+	}
+
+	return string(embedded.Replace([]byte(htmlBody), spans)), nil
+}
+
+
+// contentIDOf returns the part (before the "@") of the Content-ID for 'parcel'; made from a hash
+// of its contents.
+func contentIDOf(parcel dataurl.Parcel) string {
+	digest := sha256.Sum256(dataurl.UnsafeBytes(parcel))
+	return hex.EncodeToString(digest[:8])
+}
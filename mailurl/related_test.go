@@ -0,0 +1,173 @@
+package mailurl
+
+
+import (
+	"bytes"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+const (
+	redDot  = `data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAA=`
+	blueDot = `data:image/gif;base64,R0lGODlhAQABAAAAACw=`
+)
+
+
+func TestToCID(t *testing.T) {
+	htmlBody := `<p>Hi</p>` +
+		`<img alt="red" src="` + redDot + `">` +
+		`<IMG SRC='` + blueDot + `' alt='blue'>` +
+		`<img src=` + redDot + `>` +
+		`<img src="https://example.com/logo.png">` +
+		`<a href="` + blueDot + `">not an img</a>`
+
+	rewritten, attachments := ToCID(htmlBody, "example.com")
+
+	if expected, actual := 2, len(attachments); expected != actual {
+		t.Fatalf("Expected %d attachments (identical images de-duplicated), but actually got %d.", expected, actual)
+	}
+
+	red, blue := attachments[0], attachments[1]
+
+	expected := `<p>Hi</p>` +
+		`<img alt="red" src="cid:` + red.ContentID + `">` +
+		`<IMG SRC='cid:` + blue.ContentID + `' alt='blue'>` +
+		`<img src="cid:` + red.ContentID + `">` +
+		`<img src="https://example.com/logo.png">` +
+		`<a href="` + blueDot + `">not an img</a>`
+	if actual := rewritten; expected != actual {
+		t.Errorf("Expected rewritten HTML to be:\n%s\nbut actually was:\n%s", expected, actual)
+	}
+
+	if !strings.HasSuffix(red.ContentID, "@example.com") {
+		t.Errorf("Expected the Content-ID to end with the domain, but actually was %q.", red.ContentID)
+	}
+	if !strings.HasSuffix(red.Filename, ".png") || !strings.HasSuffix(blue.Filename, ".gif") {
+		t.Errorf("Expected file names with extensions for the media types, but actually got %q and %q.", red.Filename, blue.Filename)
+	}
+	if !dataurl.Equal(dataurl.MustParse(redDot), red.Parcel) {
+		t.Errorf("Expected the 1st attachment to be the red dot, but it was not.")
+	}
+
+
+	// And back again.
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+	for _, attachment := range attachments {
+		if err := WriteAttachment(writer, attachment); nil != err {
+			t.Fatalf("Did not expect an error, but actually got one: %v", err)
+		}
+	}
+	writer.Close()
+
+	parts := map[string]dataurl.Parcel{}
+	reader := multipart.NewReader(&buffer, writer.Boundary())
+	for {
+		part, err := reader.NextRawPart()
+		if nil != err {
+			break
+		}
+
+		if expected, actual := "inline", strings.SplitN(part.Header.Get("Content-Disposition"), ";", 2)[0]; expected != actual {
+			t.Errorf("Expected Content-Disposition %q, but actually was %q.", expected, actual)
+		}
+
+		parcel, err := FromPart(part)
+		if nil != err {
+			t.Fatalf("Did not expect an error, but actually got one: %v", err)
+		}
+		parts[part.Header.Get("Content-ID")] = parcel
+	}
+
+	displayable, err := FromCID(rewritten, parts)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	expected = `<p>Hi</p>` +
+		`<img alt="red" src="` + redDot + `">` +
+		`<IMG SRC='` + blueDot + `' alt='blue'>` +
+		`<img src="` + redDot + `">` +
+		`<img src="https://example.com/logo.png">` +
+		`<a href="` + blueDot + `">not an img</a>`
+	if actual := displayable; expected != actual {
+		t.Errorf("Expected HTML to be:\n%s\nbut actually was:\n%s", expected, actual)
+	}
+}
+
+
+func TestToCIDSameContentsDifferentMediaType(t *testing.T) {
+	htmlBody := `<img src="data:image/png;base64,AAAA"><img src="data:image/gif;base64,AAAA">`
+
+	_, attachments := ToCID(htmlBody, "")
+
+	if expected, actual := 2, len(attachments); expected != actual {
+		t.Fatalf("Expected %d attachments, but actually got %d.", expected, actual)
+	}
+	if attachments[0].ContentID == attachments[1].ContentID {
+		t.Errorf("Expected different Content-IDs, but both were %q.", attachments[0].ContentID)
+	}
+	if !strings.HasSuffix(attachments[0].ContentID, "@" + DefaultContentIDDomain) {
+		t.Errorf("Expected the default domain, but Content-ID was %q.", attachments[0].ContentID)
+	}
+}
+
+
+func TestToCIDEscapedAndSVG(t *testing.T) {
+	htmlBody := `<img src="data:image/svg+xml,%3Csvg%3E%3C/svg%3E&amp;x" alt="a > b">`
+
+	rewritten, attachments := ToCID(htmlBody, "example.com")
+
+	if expected, actual := 1, len(attachments); expected != actual {
+		t.Fatalf("Expected %d attachments, but actually got %d.", expected, actual)
+	}
+	if expected, actual := "<svg></svg>&x", attachments[0].Parcel.String(); expected != actual {
+		t.Errorf("Expected contents %q (HTML entities unescaped), but actually got %q.", expected, actual)
+	}
+	if expected := `<img src="cid:` + attachments[0].ContentID + `" alt="a > b">`; expected != rewritten {
+		t.Errorf("Expected %q, but actually got %q.", expected, rewritten)
+	}
+}
+
+
+func TestToCIDBadDataURL(t *testing.T) {
+	htmlBody := `<img src="data:not a media type,x">` +
+		`<img src=" DATA:image/gif;base64,R0lGODlhAQABAAAAACw= ">` +
+		`<img src="data:image/png;base64,!!!!">`
+
+	rewritten, attachments := ToCID(htmlBody, "example.com")
+
+	if expected, actual := 1, len(attachments); expected != actual {
+		t.Fatalf("Expected %d attachment, but actually got %d.", expected, actual)
+	}
+	if !dataurl.Equal(dataurl.MustParse(blueDot), attachments[0].Parcel) {
+		t.Errorf("Expected the attachment to be the blue dot, but it was not.")
+	}
+
+	expected := `<img src="data:not a media type,x">` +
+		`<img src="cid:` + attachments[0].ContentID + `">` +
+		`<img src="data:image/png;base64,!!!!">`
+	if actual := rewritten; expected != actual {
+		t.Errorf("Expected rewritten HTML to be:\n%s\nbut actually was:\n%s", expected, actual)
+	}
+}
+
+
+func TestToCIDNotAnImgSrc(t *testing.T) {
+	htmlBody := `<img alt=" src=` + blueDot + `" data-src="` + blueDot + `">` +
+		`<!-- <img src="` + blueDot + `"> -->` +
+		`<script>document.write('<img src="` + blueDot + `">')</script>`
+
+	rewritten, attachments := ToCID(htmlBody, "example.com")
+
+	if expected, actual := 0, len(attachments); expected != actual {
+		t.Errorf("Expected %d attachments, but actually got %d.", expected, actual)
+	}
+	if expected, actual := htmlBody, rewritten; expected != actual {
+		t.Errorf("Expected the HTML to be left as it is:\n%s\nbut actually was:\n%s", expected, actual)
+	}
+}
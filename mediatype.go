@@ -4,7 +4,9 @@ package dataurl
 import (
 	"fmt"
 	"mime"
+	"sort"
 	"strings"
+	"unicode"
 )
 
 
@@ -51,3 +53,96 @@ func sanitizeMediaType(mediaType string) (string, error) {
 
 	return mediaType, nil
 }
+
+
+// formatMediaType turns a media type, given by a user of this library, into a
+// form that can be put into a data URL.
+//
+// If 'mediaType' is the empty string, then the empty string is returned, since
+// that is how a data URL (implicitly) specifies the default media type.
+//
+// Otherwise 'mediaType' is parsed, and re-serialized (lower-casing the type and
+// the parameter names, and removing any whitespace); so that what is returned
+// can be put right after the "data:" of a data URL.
+func formatMediaType(mediaType string) (string, error) {
+	if "" == mediaType {
+		return "", nil
+	}
+
+	return rewriteMediaType(mediaType, nil)
+}
+
+
+// parseMediaType is like mime.ParseMediaType(), except that (as in a data URL) a
+// media type that starts with a ";" (ex: ";charset=utf-8") is a "text/plain" one;
+// and the error is a BadMediaTypeComplainer.
+func parseMediaType(mediaType string) (string, map[string]string, error) {
+	if strings.HasPrefix(mediaType, ";") {
+		mediaType = "text/plain" + mediaType
+	}
+
+	mimeType, params, err := mime.ParseMediaType(mediaType)
+	if nil != err {
+		return "", nil, newBadMediaTypeComplainer(err)
+	}
+
+	return mimeType, params, nil
+}
+
+
+// rewriteMediaType parses 'mediaType' (with parseMediaType()), calls 'rewrite' (if
+// it is not nil) to change its MIME type and parameters, and then formats it (with
+// formatMediaTypeParams()).
+//
+// 'rewrite' can change the parameters in place; and returns the MIME type.
+func rewriteMediaType(mediaType string, rewrite func(mimeType string, params map[string]string) string) (string, error) {
+	mimeType, params, err := parseMediaType(mediaType)
+	if nil != err {
+		return "", err
+	}
+
+	if nil != rewrite {
+		mimeType = rewrite(mimeType, params)
+	}
+
+	return formatMediaTypeParams(mimeType, params)
+}
+
+
+// formatMediaTypeParams is like mime.FormatMediaType(), except it does not put
+// a space after each semicolon; since a space does not belong in a data URL.
+//
+// A parameter value with a comma, a double quote, or whitespace in it is an error.
+// A comma would end the media type (see splitDataURL()); and the others would put
+// (escaped) quotes and raw whitespace into the data URL.
+func formatMediaTypeParams(mimeType string, params map[string]string) (string, error) {
+	// We format each parameter by itself (with mime.FormatMediaType()), and
+	// then remove what comes before the parameter.
+	var buffer strings.Builder
+
+	buffer.WriteString(strings.ToLower(mimeType))
+
+	var names []string
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if value := params[name]; strings.ContainsAny(value, ",\"") || strings.ContainsFunc(value, unicode.IsSpace) {
+			return "", newBadMediaTypeComplainer(fmt.Errorf("the value of parameter %q of media type %q (%q) cannot be put into a data URL", name, mimeType, value))
+		}
+
+		const prefix = "x/x; "
+
+		formatted := mime.FormatMediaType("x/x", map[string]string{name:params[name]})
+		if !strings.HasPrefix(formatted, prefix) {
+			return "", newBadMediaTypeComplainer(fmt.Errorf("could not format parameter %q of media type %q", name, mimeType))
+		}
+
+		buffer.WriteString(";")
+		buffer.WriteString(formatted[len(prefix):])
+	}
+
+	return buffer.String(), nil
+}
@@ -0,0 +1,34 @@
+/*
+Package mhtml converts between self-contained HTML documents (with data URLs for everything they
+refer to) and MHTML files (multipart/related web archives, as saved by web browsers).
+
+Exporting puts each (distinct) data URL in the HTML document into its own part of the MHTML file;
+with a Content-Location header, which the HTML document then refers to instead.
+
+Importing does the opposite. Each reference in the HTML document (in src, href and similar
+attributes, and in CSS url()s) to a part of the MHTML file (by its Content-Location, or by its
+Content-ID with a cid: URL) is replaced by a data URL. References in CSS parts are inlined too.
+
+Example Usage
+
+	var buffer bytes.Buffer
+
+	err := mhtml.Export(&buffer, htmlDocument)
+	if nil != err {
+		//@TODO
+	}
+
+Another Example Usage
+
+	file, err := os.Open("page.mhtml")
+	if nil != err {
+		//@TODO
+	}
+	defer file.Close()
+
+	htmlDocument, err := mhtml.Import(file)
+	if nil != err {
+		//@TODO
+	}
+*/
+package mhtml
@@ -0,0 +1,164 @@
+package mhtml
+
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+
+	"github.com/reiver/go-dataurl"
+	"github.com/reiver/go-dataurl/internal/embedded"
+	"github.com/reiver/go-dataurl/mailurl"
+)
+
+
+// DefaultBaseLocation is the default for Config.BaseLocation.
+const DefaultBaseLocation = "https://mhtml.invalid/"
+
+
+// Config is used to configure Config.Export().
+type Config struct {
+	// BaseLocation is what the Content-Location of each part starts with. It should end with a "/".
+	// If empty, then DefaultBaseLocation is used.
+	BaseLocation string
+}
+
+
+func (config Config) baseLocation() string {
+	if "" == config.BaseLocation {
+		return DefaultBaseLocation
+	}
+
+	return config.BaseLocation
+}
+
+
+// Export writes the HTML document 'htmlDocument' to 'w' as an MHTML file, using the default Config.
+//
+// See Config.Export() for more.
+func Export(w io.Writer, htmlDocument string) error {
+	return Config{}.Export(w, htmlDocument)
+}
+
+
+// Export writes the HTML document 'htmlDocument' to 'w' as an MHTML file.
+//
+// Each distinct data URL in 'htmlDocument' becomes its own part; with a Content-Location made from
+// Config.BaseLocation, a hash of its contents, and a file extension for its media type. The data URLs
+// in the HTML document are replaced with those Content-Locations. The HTML document is the first part.
+//
+// Data URLs are looked for in the attributes of the HTML document, and in the CSS url()s in its style
+// attributes and <style> elements. Things that look like data URLs, but cannot be parsed, are left as
+// they are.
+func (config Config) Export(w io.Writer, htmlDocument string) error {
+	base := config.baseLocation()
+
+	type resource struct {
+		Location string
+		Parcel   dataurl.Parcel
+	}
+
+	var resources []resource
+	locations := map[string]string{} // Canonical data URL → Content-Location.
+
+	document := []byte(htmlDocument)
+
+	var spans []embedded.Span
+	for _, match := range embedded.Find(document, embedded.SyntaxHTML) {
+		if !embedded.HasScheme(match.URL, "data:") {
+			continue
+		}
+
+		parcel, err := embedded.Parse(match.URL)
+		if nil != err {
+			continue
+		}
+
+		canonical, err := dataurl.Canonicalize("data:" + strings.TrimSpace(match.URL)[len("data:"):])
+		if nil != err {
+			continue
+		}
+
+		location, ok := locations[canonical]
+		if !ok {
+			digest := sha256.Sum256(dataurl.UnsafeBytes(parcel))
+			location = base + hex.EncodeToString(digest[:8]) + embedded.ExtensionFor(parcel.MediaType())
+
+			// Same contents but a different media type.
+			for _, r := range resources {
+				if location == r.Location {
+					location = fmt.Sprintf("%s%s-%d%s", base, hex.EncodeToString(digest[:8]), len(resources)+1, embedded.ExtensionFor(parcel.MediaType()))
+					break
+				}
+			}
+
+			locations[canonical] = location
+			resources = append(resources, resource{location, parcel})
+		}
+
+		spans = append(spans, embedded.Span{
+			Start: match.Start,
+			End:   match.End,
+			Text:  match.Escape(location),
+		})
+	}
+
+	rewritten := embedded.Replace(document, spans)
+
+	writer := multipart.NewWriter(w)
+
+	header := fmt.Sprintf("From: <Saved by go-dataurl>\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: %s\r\n" +
+		"\r\n", mime.FormatMediaType("multipart/related", map[string]string{"type": "text/html", "boundary": writer.Boundary()}))
+	if _, err := io.WriteString(w, header); nil != err {
+		return err
+	}
+
+	{
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {"text/html; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+			"Content-Location":          {base + "index.html"},
+		})
+		if nil != err {
+			return err
+		}
+
+		// Binary, so that line breaks are kept exactly as they are.
+		qp := quotedprintable.NewWriter(part)
+		qp.Binary = true
+		if _, err := qp.Write(rewritten); nil != err {
+			return err
+		}
+		if err := qp.Close(); nil != err {
+			return err
+		}
+	}
+
+	for _, r := range resources {
+		header, err := mailurl.Header(r.Parcel, "")
+		if nil != err {
+			return err
+		}
+		header.Set("Content-Location", r.Location)
+
+		part, err := writer.CreatePart(header)
+		if nil != err {
+			return err
+		}
+
+		if err := mailurl.WriteBody(part, r.Parcel); nil != err {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
@@ -0,0 +1,218 @@
+package mhtml
+
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/url"
+	"strings"
+
+	"github.com/reiver/go-dataurl"
+	"github.com/reiver/go-dataurl/internal/embedded"
+	"github.com/reiver/go-dataurl/mailurl"
+)
+
+
+// maxDepth is how deep references in CSS parts (to other CSS parts, etc) are followed.
+const maxDepth = 8
+
+
+var (
+	errNoHTMLPart = errors.New("mhtml: no text/html part")
+)
+
+
+// archive is the parts of an MHTML file.
+type archive struct {
+	byLocation  map[string]dataurl.Parcel
+	byContentID map[string]dataurl.Parcel
+	locationOf  map[dataurl.Parcel]string
+}
+
+
+// Import reads an MHTML file from 'r', and returns its HTML document; with each reference to a part
+// of the MHTML file replaced by a data URL.
+//
+// The HTML document is the part named by the "start" parameter of the Content-Type, if there is one;
+// otherwise the first text/html part.
+//
+// References to things that are not in the MHTML file are left as they are.
+func Import(r io.Reader) (string, error) {
+	message, err := mail.ReadMessage(r)
+	if nil != err {
+		return "", fmt.Errorf("mhtml: could not read MHTML file: %w", err)
+	}
+
+	mimeType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if nil != err {
+		return "", fmt.Errorf("mhtml: bad Content-Type: %w", err)
+	}
+
+	// An MHTML file with just an HTML document, and nothing else.
+	if "text/html" == mimeType {
+		parcel, err := mailurl.FromMessage(message)
+		if nil != err {
+			return "", err
+		}
+		return parcel.String(), nil
+	}
+
+	if "multipart/related" != mimeType {
+		return "", fmt.Errorf("mhtml: expected a multipart/related Content-Type, but got %q", mimeType)
+	}
+
+	a := archive{
+		byLocation:  map[string]dataurl.Parcel{},
+		byContentID: map[string]dataurl.Parcel{},
+		locationOf:  map[dataurl.Parcel]string{},
+	}
+
+	start := embedded.NormalizeContentID(params["start"])
+
+	var root dataurl.Parcel
+	var rootLocation string
+
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if io.EOF == err {
+			break
+		}
+		if nil != err {
+			return "", fmt.Errorf("mhtml: could not read part: %w", err)
+		}
+
+		parcel, err := mailurl.FromPart(part)
+		if nil != err {
+			return "", err
+		}
+
+		location := strings.TrimSpace(part.Header.Get("Content-Location"))
+		contentID := embedded.NormalizeContentID(part.Header.Get("Content-ID"))
+
+		if "" != location {
+			a.byLocation[location] = parcel
+			a.locationOf[parcel] = location
+		}
+		if "" != contentID {
+			a.byContentID[contentID] = parcel
+		}
+
+		isRoot := false
+		switch {
+		case "" != start:
+			isRoot = start == contentID
+		case nil == root:
+			partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+			isRoot = "text/html" == partType
+		}
+		if isRoot {
+			root = parcel
+			rootLocation = location
+		}
+	}
+
+	if nil == root {
+		return "", errNoHTMLPart
+	}
+
+	return a.inline(root.String(), embedded.SyntaxHTML, rootLocation, 0), nil
+}
+
+
+// inline returns 'document' (which has the syntax 'syntax') with each reference to a part replaced
+// by a data URL. 'location' is the Content-Location of the document, which relative references are
+// resolved against.
+//
+// In an HTML document, the references looked for are in the src, href, background, poster and data
+// attributes; and in CSS url()s.
+func (a archive) inline(document string, syntax embedded.Syntax, location string, depth int) string {
+	content := []byte(document)
+
+	var spans []embedded.Span
+	for _, match := range embedded.Find(content, syntax) {
+		switch match.Attribute {
+		case "", "style", "src", "href", "background", "poster", "data":
+		default:
+			continue
+		}
+
+		dataURL, ok := a.dataURLFor(match.URL, location, depth)
+		if !ok {
+			continue
+		}
+
+		spans = append(spans, embedded.Span{
+			Start: match.Start,
+			End:   match.End,
+			Text:  match.Escape(dataURL),
+		})
+	}
+
+	return string(embedded.Replace(content, spans))
+}
+
+
+// dataURLFor returns a data URL for the part that 'reference' (resolved against 'base') refers to.
+// If it is a CSS part, then the references in it are inlined too.
+func (a archive) dataURLFor(reference string, base string, depth int) (string, bool) {
+	reference = strings.TrimSpace(reference)
+	if "" == reference || embedded.HasScheme(reference, "data:") {
+		return "", false
+	}
+
+	var parcel dataurl.Parcel
+	if embedded.HasScheme(reference, "cid:") {
+		contentID, err := url.PathUnescape(reference[len("cid:"):])
+		if nil != err {
+			return "", false
+		}
+		parcel = a.byContentID[embedded.NormalizeContentID(contentID)]
+	} else {
+		parcel = a.byLocation[reference]
+		if nil == parcel {
+			parcel = a.byLocation[resolve(base, reference)]
+		}
+	}
+	if nil == parcel {
+		return "", false
+	}
+
+	mimeType, _, _ := mime.ParseMediaType(parcel.MediaType())
+	if "text/css" == mimeType && depth < maxDepth {
+		css := a.inline(parcel.String(), embedded.SyntaxCSS, a.locationOf[parcel], depth+1)
+
+		dataURL, err := dataurl.EncodeShortest(parcel.MediaType(), []byte(css))
+		if nil != err {
+			return "", false
+		}
+		return dataURL, true
+	}
+
+	dataURL, err := dataurl.EncodeShortest(parcel.MediaType(), dataurl.UnsafeBytes(parcel))
+	if nil != err {
+		return "", false
+	}
+
+	return dataURL, true
+}
+
+
+// resolve returns 'reference' resolved against 'base'; or 'reference' as is, if either cannot be parsed.
+func resolve(base string, reference string) string {
+	baseURL, err := url.Parse(base)
+	if nil != err {
+		return reference
+	}
+
+	referenceURL, err := url.Parse(reference)
+	if nil != err {
+		return reference
+	}
+
+	return baseURL.ResolveReference(referenceURL).String()
+}
@@ -0,0 +1,230 @@
+package mhtml
+
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/reiver/go-dataurl"
+	"github.com/reiver/go-dataurl/internal/embedded"
+)
+
+
+const (
+	redDot  = `data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAA=`
+	blueDot = `data:image/gif;base64,R0lGODlhAQABAAAAACw=`
+)
+
+
+func TestExportImport(t *testing.T) {
+	htmlDocument := `<!DOCTYPE html>` + "\n" +
+		`<html><head><style>body{background:url('` + blueDot + `')}</style></head>` + "\n" +
+		`<body><img src="` + redDot + `"><img src=` + redDot + `><p>caf` + "é" + `</p></body></html>`
+
+	var buffer bytes.Buffer
+	if err := Export(&buffer, htmlDocument); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	exported := buffer.String()
+	if strings.Contains(exported, "data:") {
+		t.Errorf("Did not expect any data URLs in the MHTML file, but there were:\n%s", exported)
+	}
+
+
+	// Check the parts.
+	message, err := mail.ReadMessage(strings.NewReader(exported))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	mimeType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+	if expected, actual := "multipart/related", mimeType; expected != actual {
+		t.Errorf("Expected Content-Type %q, but actually was %q.", expected, actual)
+	}
+
+	var locations []string
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if io.EOF == err {
+			break
+		}
+		if nil != err {
+			t.Fatalf("Did not expect an error, but actually got one: %v", err)
+		}
+
+		location := part.Header.Get("Content-Location")
+		if !strings.HasPrefix(location, DefaultBaseLocation) {
+			t.Errorf("Expected Content-Location to start with %q, but actually was %q.", DefaultBaseLocation, location)
+		}
+		locations = append(locations, location)
+	}
+
+	if expected, actual := 3, len(locations); expected != actual {
+		t.Fatalf("Expected %d parts (the HTML document, and 2 distinct images), but actually got %d: %v", expected, actual, locations)
+	}
+	if !strings.HasSuffix(locations[1], ".gif") || !strings.HasSuffix(locations[2], ".png") {
+		t.Errorf("Expected file extensions for the media types, but actually got: %v", locations)
+	}
+
+
+	// And back again.
+	imported, err := Import(strings.NewReader(exported))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	expected := `<!DOCTYPE html>` + "\n" +
+		`<html><head><style>body{background:url('` + blueDot + `')}</style></head>` + "\n" +
+		`<body><img src="` + redDot + `"><img src="` + redDot + `"><p>caf` + "é" + `</p></body></html>`
+	if actual := imported; expected != actual {
+		t.Errorf("Expected imported HTML document to be:\n%s\nbut actually was:\n%s", expected, actual)
+	}
+}
+
+
+func TestExportImportQuotedSVG(t *testing.T) {
+	const svg = `<svg xmlns='http://www.w3.org/2000/svg'></svg>`
+
+	htmlDocument := `<html><head><style>.a{background:url("data:image/svg+xml;charset=utf8,%3Csvg xmlns='http://www.w3.org/2000/svg'%3E%3C/svg%3E")}</style></head>` +
+		`<body><div style="background:url('data:image/svg+xml,%3Csvg xmlns=&quot;http://www.w3.org/2000/svg&quot;/%3E')"></div>` +
+		`<img src="data:image/svg+xml,%3Csvg xmlns=&#39;http://www.w3.org/2000/svg&#39;%3E%3C/svg%3E"></body></html>`
+
+	var buffer bytes.Buffer
+	if err := Export(&buffer, htmlDocument); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	exported := buffer.String()
+	if strings.Contains(exported, "data:") {
+		t.Errorf("Did not expect any data URLs in the MHTML file, but there were:\n%s", exported)
+	}
+	if strings.Contains(exported, "xmlns='") {
+		t.Errorf("Did not expect any of the SVG images to be left (partly) in the HTML document, but there were:\n%s", exported)
+	}
+
+	imported, err := Import(strings.NewReader(exported))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	expected := []string{svg, `<svg xmlns="http://www.w3.org/2000/svg"/>`, svg}
+
+	var actual []string
+	for _, match := range embedded.Find([]byte(imported), embedded.SyntaxHTML) {
+		if !embedded.HasScheme(match.URL, "data:") {
+			continue
+		}
+
+		parcel, err := dataurl.Parse(match.URL)
+		if nil != err {
+			t.Errorf("Did not expect an error, but actually got one: %v\nData URL: %q", err, match.URL)
+			continue
+		}
+		actual = append(actual, parcel.String())
+	}
+
+	if expected, actual := len(expected), len(actual); expected != actual {
+		t.Fatalf("Expected %d data URLs, but actually got %d:\n%s", expected, actual, imported)
+	}
+	for i := range expected {
+		if expected, actual := expected[i], actual[i]; expected != actual {
+			t.Errorf("For data URL #%d, expected %q, but actually got %q.", i, expected, actual)
+		}
+	}
+}
+
+
+func TestImport(t *testing.T) {
+	const mhtmlFile = "From: <Saved by Blink>\r\n" +
+		"Snapshot-Content-Location: https://example.com/page/\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/related;\r\n" +
+		"\ttype=\"text/html\";\r\n" +
+		"\tboundary=\"----MultipartBoundary--abc----\"\r\n" +
+		"\r\n" +
+		"------MultipartBoundary--abc----\r\n" +
+		"Content-Type: text/html\r\n" +
+		"Content-ID: <frame-1@mhtml.blink>\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"Content-Location: https://example.com/page/\r\n" +
+		"\r\n" +
+		"<html><head><link rel=3D\"stylesheet\" href=3D\"style.css\"></head>=\r\n" +
+		"<body><img src=3D\"../img/dot.gif\" alt=3D\"dot\"><img src=3D\"cid:logo@mhtml.blink\"><img src=3D\"missing.png\"></body></html>\r\n" +
+		"------MultipartBoundary--abc----\r\n" +
+		"Content-Type: text/css\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"Content-Location: https://example.com/page/style.css\r\n" +
+		"\r\n" +
+		"body { background: url(\"../img/dot.gif\"); }\r\n" +
+		"------MultipartBoundary--abc----\r\n" +
+		"Content-Type: image/gif\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"Content-Location: https://example.com/img/dot.gif\r\n" +
+		"\r\n" +
+		"R0lGODlhAQABAAAAACw=\r\n" +
+		"------MultipartBoundary--abc----\r\n" +
+		"Content-Type: image/png\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"Content-ID: <logo@mhtml.blink>\r\n" +
+		"\r\n" +
+		"iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAA=\r\n" +
+		"------MultipartBoundary--abc------\r\n"
+
+	imported, err := Import(strings.NewReader(mhtmlFile))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if !strings.Contains(imported, `<img src="` + blueDot + `" alt="dot">`) {
+		t.Errorf("Expected the relative reference to be inlined, but it was not:\n%s", imported)
+	}
+	if !strings.Contains(imported, `<img src="` + redDot + `">`) {
+		t.Errorf("Expected the cid: reference to be inlined, but it was not:\n%s", imported)
+	}
+	if !strings.Contains(imported, `<img src="missing.png">`) {
+		t.Errorf("Expected the reference to a missing part to be left as is, but it was not:\n%s", imported)
+	}
+
+	// The stylesheet, with its own reference (relative to it) inlined.
+	index := strings.Index(imported, `href="`)
+	if -1 == index {
+		t.Fatalf("Expected an href, but there was not one:\n%s", imported)
+	}
+	styleDataURL := imported[index+len(`href="`):]
+	styleDataURL = styleDataURL[:strings.IndexByte(styleDataURL, '"')]
+
+	parcel, err := dataurl.Parse(styleDataURL)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v\nData URL: %q", err, styleDataURL)
+	}
+	if expected, actual := `body { background: url("` + blueDot + `"); }`, parcel.String(); expected != actual {
+		t.Errorf("Expected the stylesheet to be %q, but actually was %q.", expected, actual)
+	}
+}
+
+
+func TestImportNoHTML(t *testing.T) {
+	const mhtmlFile = "MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/related; boundary=b\r\n" +
+		"\r\n" +
+		"--b\r\n" +
+		"Content-Type: image/gif\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"\r\n" +
+		"R0lGODlhAQABAAAAACw=\r\n" +
+		"--b--\r\n"
+
+	if _, err := Import(strings.NewReader(mhtmlFile)); nil == err {
+		t.Errorf("Expected an error, but did not actually get one.")
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"bytes"
 	"io"
 	"strings"
+	"unsafe"
 )
 
 
+// emptyDefaultParcel is shared. This is OK since a Parcel is immutable.
 var (
 	emptyDefaultParcel = newParcel()
 )
@@ -43,6 +45,24 @@ var (
 //	contents := parcel.String() // == "Hello"
 //	
 //	mediaType := parcel.MediaType() // == "application/x-apple-banana-cherry;charset=US-ASCII"
+//
+// A Parcel is immutable. So it is safe to use a Parcel from multiple
+// goroutines at the same time. (And dataurl.Parse() may return the very
+// same Parcel for different calls.)
+//
+// Bytes returns a copy of the contents; so modifying what it returns does
+// not affect the Parcel. dataurl.UnsafeBytes() returns the contents without
+// copying; but what it returns MUST NOT be modified.
+//
+// For callers that need more than an io.Reader, there are the dataurl.ReadSeeker(),
+// dataurl.ReaderAt(), dataurl.WriteTo() and dataurl.Len() funcs. For a Parcel from
+// this library, none of these copy the contents.
+//
+// For example:
+//
+//	http.ServeContent(w, r, "", modtime, dataurl.ReadSeeker(parcel))
+//
+//	zipReader, err := zip.NewReader(dataurl.ReaderAt(parcel), int64(dataurl.Len(parcel)))
 type Parcel interface {
 	Bytes() []byte
 	Reader() io.Reader
@@ -53,9 +73,15 @@ type Parcel interface {
 }
 
 
+// internalParcel is the only underlying implementation that fits the Parcel
+// interface, in this library.
+//
+// The contents are stored as a string (rather than a []byte) so that they can
+// be shared without being copied. For example, the contents of a percent encoded
+// data URL without any escapes in it are just a slice of the data URL itself.
 type internalParcel struct {
-	buffer bytes.Buffer
-	mediaType    string
+	content   string
+	mediaType string
 }
 
 
@@ -68,23 +94,158 @@ func newParcel() *internalParcel {
 }
 
 
+// NewParcel returns a Parcel with the media type 'mediaType' and the contents 'data';
+// without having to create (and parse) a data URL.
+//
+// 'mediaType' is handled the same way as the media type in a data URL. So, if it is the
+// empty string, then the media type is "text/plain;charset=US-ASCII". And if it does not
+// have a charset, then "charset=US-ASCII" is added.
+//
+// 'data' is copied; so modifying it afterwards does not affect the returned Parcel.
+//
+// Example usage:
+//
+//	parcel, err := dataurl.NewParcel("image/png", pngData)
+//	if nil != err {
+//		//@TODO
+//	}
+func NewParcel(mediaType string, data []byte) (Parcel, error) {
+	mediaType, err := sanitizeMediaType(mediaType)
+	if nil != err {
+		return nil, err
+	}
+
+	parcel := newParcel()
+	parcel.mediaType = mediaType
+	parcel.content = string(data)
+
+	return parcel, nil
+}
+
+
+// Bytes returns a copy of the contents of the parcel.
 func (parcel *internalParcel) Bytes() []byte {
-	return parcel.buffer.Bytes()
+	return []byte(parcel.content)
+}
+
+
+// UnsafeBytes returns the contents of 'parcel'. For a Parcel from this library (ex: from
+// dataurl.Parse()) it does so without copying. For any other Parcel it is the same as
+// parcel.Bytes().
+//
+// The returned []byte may share memory with the parcel (and maybe with the data URL that
+// was parsed); so it MUST NOT be modified. Modifying it would change the contents of every
+// user of the parcel, in every goroutine.
+//
+// Example usage:
+//
+//	digest := sha256.Sum256(dataurl.UnsafeBytes(parcel))
+func UnsafeBytes(parcel Parcel) []byte {
+	if p, ok := parcel.(*internalParcel); ok {
+		return p.unsafeBytes()
+	}
+
+	return parcel.Bytes()
+}
+
+
+// unsafeBytes returns the contents of the parcel without copying. See UnsafeBytes().
+func (parcel *internalParcel) unsafeBytes() []byte {
+	if "" == parcel.content {
+		return []byte{}
+	}
+
+	return unsafe.Slice(unsafe.StringData(parcel.content), len(parcel.content))
 }
 
 
 func (parcel *internalParcel) Reader() io.Reader {
-	return strings.NewReader( parcel.buffer.String() )
+	return strings.NewReader(parcel.content)
+}
+
+
+// ReadSeeker returns an io.ReadSeeker of the contents of 'parcel'. For a Parcel from this library
+// (ex: from dataurl.Parse()) it does so without copying.
+//
+// Each call returns a new io.ReadSeeker, starting at the beginning of the contents.
+//
+// Example usage:
+//
+//	http.ServeContent(w, r, "", modtime, dataurl.ReadSeeker(parcel))
+func ReadSeeker(parcel Parcel) io.ReadSeeker {
+	if p, ok := parcel.(*internalParcel); ok {
+		return strings.NewReader(p.content)
+	}
+
+	if readSeeker, ok := parcel.Reader().(io.ReadSeeker); ok {
+		return readSeeker
+	}
+
+	return bytes.NewReader(parcel.Bytes())
+}
+
+
+// ReaderAt returns an io.ReaderAt of the contents of 'parcel'. For a Parcel from this library
+// (ex: from dataurl.Parse()) it does so without copying.
+//
+// Example usage:
+//
+//	zipReader, err := zip.NewReader(dataurl.ReaderAt(parcel), int64(dataurl.Len(parcel)))
+func ReaderAt(parcel Parcel) io.ReaderAt {
+	if p, ok := parcel.(*internalParcel); ok {
+		return strings.NewReader(p.content)
+	}
+
+	if readerAt, ok := parcel.Reader().(io.ReaderAt); ok {
+		return readerAt
+	}
+
+	return bytes.NewReader(parcel.Bytes())
+}
+
+
+// WriteTo writes the contents of 'parcel' to 'w'. For a Parcel from this library (ex: from
+// dataurl.Parse()) it does so without copying.
+//
+// Example usage:
+//
+//	n, err := dataurl.WriteTo(w, parcel)
+func WriteTo(w io.Writer, parcel Parcel) (int64, error) {
+	if writerTo, ok := parcel.(io.WriterTo); ok {
+		return writerTo.WriteTo(w)
+	}
+
+	return io.Copy(w, parcel.Reader())
+}
+
+
+// Len returns the length of the contents of 'parcel', in bytes. For a Parcel from this library
+// (ex: from dataurl.Parse()) it does so without copying.
+func Len(parcel Parcel) int {
+	if p, ok := parcel.(*internalParcel); ok {
+		return len(p.content)
+	}
+
+	return len(parcel.Bytes())
+}
+
+
+// WriteTo writes the contents of the parcel to 'w', without copying.
+//
+// WriteTo makes a Parcel from this library an io.WriterTo. (So, for example, io.Copy() uses it.)
+func (parcel *internalParcel) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, parcel.content)
+	return int64(n), err
 }
 
 
 func (parcel *internalParcel) Runes() []rune {
-	return []rune(parcel.buffer.String())
+	return []rune(parcel.content)
 }
 
 
 func (parcel *internalParcel) String() string {
-	return parcel.buffer.String()
+	return parcel.content
 }
 
 
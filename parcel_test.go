@@ -0,0 +1,278 @@
+package dataurl
+
+
+import (
+	"bytes"
+	"crypto"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+
+func TestParcelBytesIsACopy(t *testing.T) {
+
+	dataURLs := []string{
+		`data:,`,
+		`data:,no_escapes_here`,
+		`data:,A%20brief%20note`,
+		`data:text/plain;charset=utf-8;base64,VGhpcyBpcyBhIHRlc3Qh`,
+	}
+
+
+	for testNumber, dataURL := range dataURLs {
+		parcel := MustParse(dataURL)
+		original := parcel.String()
+
+		p := parcel.Bytes()
+		for i := range p {
+			p[i] = 'X'
+		}
+		_ = append(p[:0], "corrupted"...)
+
+		if expected, actual := original, parcel.String(); expected != actual {
+			t.Errorf("For test #%d, expected the contents to still be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+		if expected, actual := original, string(MustParse(dataURL).Bytes()); expected != actual {
+			t.Errorf("For test #%d, expected the contents of a newly parsed parcel to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+	}
+}
+
+
+func TestParcelUnsafeBytes(t *testing.T) {
+	parcel := MustParse(`data:,A%20brief%20note`)
+
+	if expected, actual := []byte("A brief note"), UnsafeBytes(parcel); !bytes.Equal(expected, actual) {
+		t.Errorf("Expected %q, but actually got %q.", expected, actual)
+	}
+
+	if actual := UnsafeBytes(MustParse(`data:,`)); nil == actual || 0 != len(actual) {
+		t.Errorf("Expected an empty (non-nil) []byte, but actually got %#v.", actual)
+	}
+}
+
+
+// TestParcelConcurrency is meant to be run with the race detector:
+//
+//	go test -race
+func TestParcelConcurrency(t *testing.T) {
+
+	parcels := []Parcel{
+		MustParse(`data:,`),
+		MustParse(`data:text/plain,`), // Same (shared) parcel as the one above.
+		MustParse(`data:,no_escapes_here`),
+		MustParse(`data:text/plain;charset=utf-8;base64,VGhpcyBpcyBhIHRlc3Qh`),
+	}
+
+	var originals []string
+	for _, parcel := range parcels {
+		originals = append(originals, parcel.String())
+	}
+
+	var waitGroup sync.WaitGroup
+	for goroutine := 0; goroutine < 8; goroutine++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+
+			for i := 0; i < 100; i++ {
+				for _, parcel := range parcels {
+					p := parcel.Bytes()
+					for j := range p {
+						p[j] ^= 0xFF
+					}
+
+					_ = UnsafeBytes(parcel)
+					_ = parcel.Runes()
+					_ = parcel.MediaType()
+					_, _ = Integrity(parcel, crypto.SHA384)
+					_, _ = Digest(parcel, crypto.SHA256)
+					_, _ = io.ReadAll(parcel.Reader())
+
+					// Parsing returns the shared empty parcel.
+					_ = MustParse(`data:;base64,`).String()
+				}
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	for i, parcel := range parcels {
+		if expected, actual := originals[i], parcel.String(); expected != actual {
+			t.Errorf("For parcel #%d, expected the contents to still be %q, but actually was %q.", i, expected, actual)
+		}
+	}
+}
+
+
+func TestParcelReadSeeker(t *testing.T) {
+	parcel := MustParse(`data:,0123456789`)
+
+	readSeeker := ReadSeeker(parcel)
+
+	if _, err := readSeeker.Seek(-4, io.SeekEnd); nil != err {
+		t.Fatalf("Did not expect an error when seeking, but actually got one: %v", err)
+	}
+
+	p, err := io.ReadAll(readSeeker)
+	if nil != err {
+		t.Fatalf("Did not expect an error when reading, but actually got one: %v", err)
+	}
+
+	if expected, actual := "6789", string(p); expected != actual {
+		t.Errorf("Expected %q, but actually got %q.", expected, actual)
+	}
+}
+
+
+func TestParcelReaderAt(t *testing.T) {
+	parcel := MustParse(`data:,0123456789`)
+
+	p := make([]byte, 3)
+	n, err := ReaderAt(parcel).ReadAt(p, 7)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if expected, actual := "789", string(p[:n]); expected != actual {
+		t.Errorf("Expected %q, but actually got %q.", expected, actual)
+	}
+
+	if _, err := ReaderAt(parcel).ReadAt(p, 9); io.EOF != err {
+		t.Errorf("Expected io.EOF when reading past the end, but actually got: %v", err)
+	}
+}
+
+
+func TestParcelWriteTo(t *testing.T) {
+	parcel := MustParse(`data:text/plain;charset=utf-8;base64,VGhpcyBpcyBhIHRlc3Qh`)
+
+	var buffer bytes.Buffer
+	n, err := WriteTo(&buffer, parcel)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if expected, actual := int64(Len(parcel)), n; expected != actual {
+		t.Errorf("Expected %d bytes to be written, but actually was %d.", expected, actual)
+	}
+	if expected, actual := "This is a test!", buffer.String(); expected != actual {
+		t.Errorf("Expected %q, but actually got %q.", expected, actual)
+	}
+	if expected, actual := 15, Len(parcel); expected != actual {
+		t.Errorf("Expected length %d, but actually got %d.", expected, actual)
+	}
+}
+
+
+// otherParcel is a Parcel that is not from this library.
+type otherParcel string
+
+func (parcel otherParcel) Bytes() []byte     { return []byte(parcel) }
+func (parcel otherParcel) Reader() io.Reader { return strings.NewReader(string(parcel)) }
+func (parcel otherParcel) Runes() []rune     { return []rune(string(parcel)) }
+func (parcel otherParcel) String() string    { return string(parcel) }
+func (parcel otherParcel) MediaType() string { return "text/plain;charset=US-ASCII" }
+
+
+func TestParcelViewsOfOtherParcel(t *testing.T) {
+	parcel := otherParcel("0123456789")
+
+	if expected, actual := 10, Len(parcel); expected != actual {
+		t.Errorf("Expected length %d, but actually got %d.", expected, actual)
+	}
+
+	if expected, actual := "0123456789", string(UnsafeBytes(parcel)); expected != actual {
+		t.Errorf("Expected %q, but actually got %q.", expected, actual)
+	}
+
+	readSeeker := ReadSeeker(parcel)
+	if _, err := readSeeker.Seek(-4, io.SeekEnd); nil != err {
+		t.Fatalf("Did not expect an error when seeking, but actually got one: %v", err)
+	}
+	if p, err := io.ReadAll(readSeeker); nil != err {
+		t.Errorf("Did not expect an error when reading, but actually got one: %v", err)
+	} else if expected, actual := "6789", string(p); expected != actual {
+		t.Errorf("Expected %q, but actually got %q.", expected, actual)
+	}
+
+	p := make([]byte, 3)
+	if n, err := ReaderAt(parcel).ReadAt(p, 2); nil != err {
+		t.Errorf("Did not expect an error, but actually got one: %v", err)
+	} else if expected, actual := "234", string(p[:n]); expected != actual {
+		t.Errorf("Expected %q, but actually got %q.", expected, actual)
+	}
+
+	var buffer bytes.Buffer
+	if _, err := WriteTo(&buffer, parcel); nil != err {
+		t.Errorf("Did not expect an error, but actually got one: %v", err)
+	} else if expected, actual := "0123456789", buffer.String(); expected != actual {
+		t.Errorf("Expected %q, but actually got %q.", expected, actual)
+	}
+}
+
+
+func TestNewParcel(t *testing.T) {
+
+	tests := []struct{
+		MediaType         string
+		Data              []byte
+		ExpectedMediaType string
+	}{
+		{
+			MediaType:         "",
+			Data:              []byte("Hello"),
+			ExpectedMediaType: "text/plain;charset=US-ASCII",
+		},
+		{
+			MediaType:         ";charset=utf-8",
+			Data:              []byte("Hello"),
+			ExpectedMediaType: "text/plain;charset=utf-8",
+		},
+		{
+			MediaType:         "image/png",
+			Data:              []byte("\x89PNG"),
+			ExpectedMediaType: "image/png;charset=US-ASCII",
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		parcel, err := NewParcel(test.MediaType, test.Data)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if expected, actual := test.ExpectedMediaType, parcel.MediaType(); expected != actual {
+			t.Errorf("For test #%d, expected media type to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+
+		// Must be the same as parsing a data URL.
+		dataURL := MustEncode(test.MediaType, test.Data, EncodingBase64)
+		if expected, actual := MustParse(dataURL).MediaType(), parcel.MediaType(); expected != actual {
+			t.Errorf("For test #%d, expected media type to be %q (same as parsing), but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+	}
+
+
+	data := []byte("abc")
+	parcel, err := NewParcel("", data)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+	data[0] = 'X'
+	if expected, actual := "abc", parcel.String(); expected != actual {
+		t.Errorf("Expected contents to be %q (not affected by modifying the []byte), but actually was %q.", expected, actual)
+	}
+
+	if _, err := NewParcel("not a media type", nil); nil == err {
+		t.Errorf("Expected an error for a bad media type, but did not actually get one.")
+	}
+}
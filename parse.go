@@ -3,7 +3,6 @@ package dataurl
 
 import (
 	"encoding/base64"
-	"net/url"
 	"strings"
 )
 
@@ -15,14 +14,13 @@ const (
 )
 
 
-var (
-	errSyntaxErrorNoComma = newSyntaxErrorComplainer("Data URL does not contain a comma.")
-)
-
-
 // Parse parses a data URL contained in parameter 'dataURL', and if it
 // contained a valid data URL, returns a Parcel, else returns an error.
 //
+// The media type (and the ";base64", if there is one) ends at the first comma.
+// Everything after it is the (encoded) contents; even if it has a ";base64,"
+// in it.
+//
 // Example usage:
 //
 //	parcel, err := dataurl.Parse("data:,Hello%20world!")
@@ -32,6 +30,78 @@ var (
 //	
 //	fmt.Println(parcel.String()) // parcel.String() == "Hello world!"
 func Parse(dataURL string) (Parcel, error) {
+	parcel, err := parse(dataURL)
+	if nil != err {
+		return nil, err
+	}
+
+	return parcel, nil
+}
+
+
+// ParseConfig is used to configure ParseConfig.Parse().
+//
+// The zero value of ParseConfig parses the same way as dataurl.Parse().
+type ParseConfig struct {
+	// Decompress is whether compressed contents (see dataurl.ContentEncoding()) are decompressed
+	// (with dataurl.Decompress()) as part of parsing.
+	Decompress bool
+
+	// DecompressionLimits are the limits used when decompressing. (The zero value uses the defaults.)
+	DecompressionLimits DecompressionLimits
+}
+
+
+// Parse is like dataurl.Parse(), except that it does what the ParseConfig specifies.
+//
+// Example usage:
+//
+//	config := dataurl.ParseConfig{
+//		Decompress: true,
+//	}
+//
+//	parcel, err := config.Parse("data:application/json;content-encoding=gzip;base64,H4sIAAAAAAAC/6pWSlSyUkpSqgUMAJxc9msJAAAA")
+//	if nil != err {
+//		//@TODO
+//	}
+//
+//	fmt.Println(parcel.String()) // {"a":"b"}
+func (config ParseConfig) Parse(dataURL string) (Parcel, error) {
+	parcel, err := Parse(dataURL)
+	if nil != err {
+		return nil, err
+	}
+
+	if config.Decompress {
+		return Decompress(parcel, config.DecompressionLimits)
+	}
+
+	return parcel, nil
+}
+
+
+// ParseBytes is like dataurl.Parse(), except that the data URL is passed as a []byte.
+//
+// ParseBytes copies. 'dataURL' is copied (once, whole) into a string, which is then parsed as
+// dataurl.Parse() would; so modifying 'dataURL' afterwards does not affect the returned Parcel.
+// That is one more allocation (and one more copy of the data URL) than dataurl.Parse(). (A
+// Parcel is immutable; so it cannot share memory with a []byte that the caller could modify.)
+//
+// If the data URL is already a string, then use dataurl.Parse() instead.
+//
+// Example usage:
+//
+//	parcel, err := dataurl.ParseBytes(line)
+//	if nil != err {
+//		//@TODO
+//	}
+func ParseBytes(dataURL []byte) (Parcel, error) {
+	return Parse(string(dataURL))
+}
+
+
+// parse does the work for Parse and ParseBytes.
+func parse(dataURL string) (*internalParcel, error) {
 
 	// If it doesn't start with "data:", then it isn't a data URL.
 	// If that's the case, then return the appropriate error.
@@ -83,22 +153,18 @@ func Parse(dataURL string) (Parcel, error) {
 	var mediaType string
 	var encoded   string
 	{
-		indexOfSemicolonBase64Comma := strings.Index(dataURL, semicolonBase64Comma)
-		indexOfComma                := strings.Index(dataURL, comma)
+		index, base64Encoded := splitDataURL(dataURL)
+		if -1 == index {
+			return nil, newSyntaxErrorComplainerAt(len(dataURL), "Data URL does not contain a comma.")
+		}
 
-		index := -1
-		if -1 != indexOfSemicolonBase64Comma {
-			index = indexOfSemicolonBase64Comma
+		if base64Encoded {
 			encoding = encodingBase64
 			encoded = dataURL[index+len(semicolonBase64Comma):]
-		} else if -1 != indexOfComma {
-			index = indexOfComma
+		} else {
 			encoding = encodingUrl
 			encoded = dataURL[index+len(comma):]
 		}
-		if -1 == index {
-			return nil, errSyntaxErrorNoComma
-		}
 
 		var err error
 
@@ -128,21 +194,29 @@ func Parse(dataURL string) (Parcel, error) {
 	// (Try to) set the contents in the parcel.
 	switch encoding {
 	case encodingBase64:
-		bs, err := base64.StdEncoding.DecodeString(encoded)
+		content, err := base64Decode(encoded)
 		if nil != err {
 //@TODO: Could this error be improved? Maybe even wrapped?
-			return nil, newSyntaxErrorComplainer("%s", err.Error())
+			offset := -1
+			if corrupt, ok := err.(base64.CorruptInputError); ok {
+				offset = len(dataURL) - len(encoded) + int(corrupt)
+			}
+			return nil, newSyntaxErrorComplainerAt(offset, "%s", err.Error())
 		}
 
-		parcel.buffer.Write(bs)
+		parcel.content = content
 	case encodingUrl:
-		s, err := url.QueryUnescape(encoded)
+		content, err := percentDecode(encoded)
 		if nil != err {
 //@TODO: Could this error be improved? Maybe even wrapped?
-			return nil, newSyntaxErrorComplainer("%s", err.Error())
+			offset := -1
+			if escape, ok := err.(escapeError); ok {
+				offset = len(dataURL) - len(encoded) + escape.offset
+			}
+			return nil, newSyntaxErrorComplainerAt(offset, "%s", err.Error())
 		}
 
-		parcel.buffer.WriteString(s)
+		parcel.content = content
 	default:
 		// This should never happen.
 		return nil, newInternalErrorComplainer("Something weird happened. It seems like there is an unknown encoding type for the data URL (other than either base64 encoded or URL encoded), but that shouldn't be possible.")
@@ -153,6 +227,28 @@ func Parse(dataURL string) (Parcel, error) {
 }
 
 
+// splitDataURL returns where the media type of 'dataURL' ends; which is where either the
+// ";base64," or the "," is. And returns whether it is base64 encoded.
+//
+// The media type ends at the first comma. So a ";base64," in the contents of a percent
+// encoded data URL (ex: a data URL of CSS, that itself has a data URL in it) does not count.
+//
+// If there isn't a comma, then -1 is returned.
+func splitDataURL(dataURL string) (int, bool) {
+	index := strings.Index(dataURL, comma)
+	if -1 == index {
+		return -1, false
+	}
+
+	const semicolonBase64 = ";base64"
+	if strings.HasSuffix(dataURL[:index], semicolonBase64) {
+		return index - len(semicolonBase64), true
+	}
+
+	return index, false
+}
+
+
 // MustParse is like dataurl.Parse(), expect it only returns a Parcel, and
 // panic()s if there was an error parsing 'dataURL'.
 //
@@ -0,0 +1,83 @@
+package dataurl
+
+
+import (
+	"strings"
+	"testing"
+)
+
+
+var (
+	benchmarkPercentNoEscapes = "data:text/plain;charset=utf-8," + strings.Repeat("Hello_world!", 1024)
+	benchmarkPercentEscapes   = "data:text/plain;charset=utf-8," + strings.Repeat("Hello%20world!", 1024)
+	benchmarkBase64           = "data:application/octet-stream;charset=utf-8;base64," + strings.Repeat("SGVsbG8gd29ybGQh", 1024)
+)
+
+
+func benchmarkParse(b *testing.B, dataURL string) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(dataURL)))
+
+	for i := 0; i < b.N; i++ {
+		if _, err := Parse(dataURL); nil != err {
+			b.Fatalf("Did not expect an error, but actually got one: %v", err)
+		}
+	}
+}
+
+
+// benchmarkParseBytes should report exactly one more allocation per operation than benchmarkParse;
+// for the copy of the data URL. (See TestParseBytesAllocations.)
+func benchmarkParseBytes(b *testing.B, dataURL string) {
+	p := []byte(dataURL)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(p)))
+
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseBytes(p); nil != err {
+			b.Fatalf("Did not expect an error, but actually got one: %v", err)
+		}
+	}
+}
+
+
+func BenchmarkParsePercentNoEscapes(b *testing.B) {
+	benchmarkParse(b, benchmarkPercentNoEscapes)
+}
+
+
+func BenchmarkParsePercentEscapes(b *testing.B) {
+	benchmarkParse(b, benchmarkPercentEscapes)
+}
+
+
+func BenchmarkParseBase64(b *testing.B) {
+	benchmarkParse(b, benchmarkBase64)
+}
+
+
+func BenchmarkParseBytesPercentNoEscapes(b *testing.B) {
+	benchmarkParseBytes(b, benchmarkPercentNoEscapes)
+}
+
+
+func BenchmarkParseBytesPercentEscapes(b *testing.B) {
+	benchmarkParseBytes(b, benchmarkPercentEscapes)
+}
+
+
+func BenchmarkParseBytesBase64(b *testing.B) {
+	benchmarkParseBytes(b, benchmarkBase64)
+}
+
+
+func BenchmarkParcelReader(b *testing.B) {
+	parcel := MustParse(benchmarkBase64)
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_ = parcel.Reader()
+	}
+}
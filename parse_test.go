@@ -318,6 +318,12 @@ func TestParse(t *testing.T) {
 			ExpectedContent:      "שלום",
 
 		},
+		// A ";base64," in the contents of a percent encoded data URL does not make it base64 encoded.
+		{
+			DataURL: `data:text/css,a{background:url(data:image/gif;base64,R0lGODlhAQABAAAAACw=)}`,
+			ExpectedMediaType: "text/css;charset=US-ASCII",
+			ExpectedContent:   `a{background:url(data:image/gif;base64,R0lGODlhAQABAAAAACw=)}`,
+		},
 	}
 
 
@@ -444,3 +450,234 @@ func TestParseFail(t *testing.T) {
 
 	}
 }
+
+
+func TestSyntaxErrorOffset(t *testing.T) {
+
+	tests := []struct{
+		DataURL  string
+		Expected int
+	}{
+		{
+			DataURL:  `data:text/plain`,
+			Expected: 15,
+		},
+		{
+			DataURL:  `data:,Hello%2world`,
+			Expected: 11,
+		},
+		{
+			DataURL:  `data:text/plain;charset=utf-8,100%`,
+			Expected: 33,
+		},
+		{
+			DataURL:  `data:;base64,SGVs*G8=`,
+			Expected: 17,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		_, err := Parse(test.DataURL)
+		if nil == err {
+			t.Errorf("For test #%d, expected an error, but did not actually get one.\nData URL: %q", testNumber, test.DataURL)
+			continue
+		}
+		if _, ok := err.(SyntaxErrorComplainer); !ok {
+			t.Errorf("For test #%d, expected a SyntaxErrorComplainer, but actually got %T: %v", testNumber, err, err)
+			continue
+		}
+
+		offset, ok := SyntaxErrorOffset(err)
+		if !ok {
+			t.Errorf("For test #%d, expected an offset, but did not actually get one.\nData URL: %q", testNumber, test.DataURL)
+			continue
+		}
+		if expected, actual := test.Expected, offset; expected != actual {
+			t.Errorf("For test #%d, expected offset %d, but actually got %d.\nData URL: %q", testNumber, expected, actual, test.DataURL)
+			continue
+		}
+	}
+
+	if _, ok := SyntaxErrorOffset(errNotADataUrl); ok {
+		t.Errorf("Did not expect an offset for an error that is not a syntax error, but actually got one.")
+	}
+}
+
+
+func TestSplitDataURL(t *testing.T) {
+
+	tests := []struct{
+		DataURL               string
+		ExpectedIndex         int
+		ExpectedBase64Encoded bool
+	}{
+		{
+			DataURL:               `data:,Hello%20world!`,
+			ExpectedIndex:         5,
+			ExpectedBase64Encoded: false,
+		},
+		{
+			DataURL:               `data:text/plain;base64,SGk=`,
+			ExpectedIndex:         15,
+			ExpectedBase64Encoded: true,
+		},
+		{
+			// The ";base64," is in the (percent encoded) contents; not the media type.
+			DataURL:               `data:text/css,a{background:url(data:image/png;base64,iVBORw0KGgo=)}`,
+			ExpectedIndex:         13,
+			ExpectedBase64Encoded: false,
+		},
+		{
+			DataURL:               `data:text/plain;charset=utf-8,a,b;base64,c`,
+			ExpectedIndex:         29,
+			ExpectedBase64Encoded: false,
+		},
+		{
+			DataURL:               `data:text/plain;base64`,
+			ExpectedIndex:         -1,
+			ExpectedBase64Encoded: false,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		index, base64Encoded := splitDataURL(test.DataURL)
+
+		if expected, actual := test.ExpectedIndex, index; expected != actual {
+			t.Errorf("For test #%d, expected index %d, but actually got %d.\nData URL: %q", testNumber, expected, actual, test.DataURL)
+			continue
+		}
+		if expected, actual := test.ExpectedBase64Encoded, base64Encoded; expected != actual {
+			t.Errorf("For test #%d, expected base64 encoded to be %t, but actually got %t.\nData URL: %q", testNumber, expected, actual, test.DataURL)
+			continue
+		}
+	}
+}
+
+
+func TestParseBytes(t *testing.T) {
+
+	tests := []struct{
+		DataURL           string
+		ExpectedMediaType string
+		ExpectedContent   string
+	}{
+		{
+			DataURL:           `data:,`,
+			ExpectedMediaType: "text/plain;charset=US-ASCII",
+			ExpectedContent:   ``,
+		},
+		{
+			DataURL:           `data:,A%20brief%20note`,
+			ExpectedMediaType: "text/plain;charset=US-ASCII",
+			ExpectedContent:   `A brief note`,
+		},
+		{
+			DataURL:           `data:text/plain;charset=utf-8,no_escapes_here`,
+			ExpectedMediaType: "text/plain;charset=utf-8",
+			ExpectedContent:   `no_escapes_here`,
+		},
+		{
+			DataURL:           `data:,1+1`,
+			ExpectedMediaType: "text/plain;charset=US-ASCII",
+			ExpectedContent:   `1 1`,
+		},
+		{
+			DataURL:           `data:text/plain;charset=utf-8;base64,VGhpcyBpcyBhIHRlc3Qh`,
+			ExpectedMediaType: "text/plain;charset=utf-8",
+			ExpectedContent:   `This is a test!`,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		p := []byte(test.DataURL)
+
+		parcel, err := ParseBytes(p)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if expected, actual := test.ExpectedMediaType, parcel.MediaType(); expected != actual {
+			t.Errorf("For test #%d, expected media type %q, but actually got %q.", testNumber, expected, actual)
+			continue
+		}
+		if expected, actual := test.ExpectedContent, parcel.String(); expected != actual {
+			t.Errorf("For test #%d, expected content %q, but actually got %q.", testNumber, expected, actual)
+			continue
+		}
+	}
+
+	{
+		p := []byte(`data:text/plain;charset=utf-8,no_escapes_here`)
+
+		parcel, err := ParseBytes(p)
+		if nil != err {
+			t.Fatalf("Did not expect an error, but actually got one: %v", err)
+		}
+
+		copy(p, "DATA:TEXT/PLAIN;CHARSET=UTF-8,NO_ESCAPES_HERE")
+
+		if expected, actual := "text/plain;charset=utf-8", parcel.MediaType(); expected != actual {
+			t.Errorf("After modifying the []byte, expected media type %q, but actually got %q.", expected, actual)
+		}
+		if expected, actual := "no_escapes_here", parcel.String(); expected != actual {
+			t.Errorf("After modifying the []byte, expected content %q, but actually got %q.", expected, actual)
+		}
+	}
+
+	for testNumber, dataURL := range []string{``, `http://example.com/`, `data:`, `data:,%zz`, `data:;base64,!!!!`} {
+		parcel, err := ParseBytes([]byte(dataURL))
+		if nil == err {
+			t.Errorf("For fail test #%d, expected an error, but actually did not get one.", testNumber)
+			continue
+		}
+		if nil != parcel {
+			t.Errorf("For fail test #%d, expected a returned parcel to be nil, but actually got: %v", testNumber, parcel)
+			continue
+		}
+	}
+}
+
+
+func TestParseAllocations(t *testing.T) {
+	const dataURL = `data:text/plain;charset=US-ASCII,no_escapes_here`
+
+	// Only the media type is parsed (with mime.ParseMediaType()), and the parcel is
+	// allocated. The contents are not copied.
+	withoutEscapes := testing.AllocsPerRun(100, func() {
+		Parse(dataURL)
+	})
+
+	withEscapes := testing.AllocsPerRun(100, func() {
+		Parse(dataURL + "%20")
+	})
+
+	// The exact number of allocations depends on the version of Go (ex: of mime.ParseMediaType());
+	// so only check that decoding the contents does not allocate more than once.
+	if limit, actual := withoutEscapes+1, withEscapes; limit < actual {
+		t.Errorf("Expected decoding percent escapes to allocate at most once more (%v allocations), but actually was %v allocations.", limit, actual)
+	}
+}
+
+
+func TestParseBytesAllocations(t *testing.T) {
+	for testNumber, dataURL := range []string{benchmarkPercentNoEscapes, benchmarkPercentEscapes, benchmarkBase64} {
+		p := []byte(dataURL)
+
+		parseAllocations := testing.AllocsPerRun(100, func() {
+			Parse(dataURL)
+		})
+
+		parseBytesAllocations := testing.AllocsPerRun(100, func() {
+			ParseBytes(p)
+		})
+
+		// ParseBytes copies the data URL (once), and then does what Parse does.
+		if expected, actual := parseAllocations+1, parseBytesAllocations; expected != actual {
+			t.Errorf("For test #%d, expected ParseBytes to allocate %v times, but actually was %v times.", testNumber, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package dataurl
+
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+
+// SchemaConfig is used to configure SchemaConfig.Validate().
+//
+// It corresponds to the JSON Schema keywords for strings that have content embedded in them:
+//
+//	{"type": "string", "contentEncoding": "base64", "contentMediaType": "image/png"}
+//
+// Which would be:
+//
+//	dataurl.SchemaConfig{MediaType: "image/png", Base64Only: true}
+type SchemaConfig struct {
+	// MediaType is what the media type of the data URL must match. It is either an exact media
+	// type (ex: "image/png"), or has a wildcard subtype (ex: "image/*"). Parameters (ex: a charset)
+	// are not compared.
+	//
+	// If empty, then any media type is allowed.
+	MediaType string
+
+	// MaxDecodedSize is the most bytes the (decoded) contents of the data URL may be.
+	// If zero (or negative), then there is no limit.
+	MaxDecodedSize int64
+
+	// Base64Only is whether the data URL must be base64 encoded (rather than percent encoded).
+	Base64Only bool
+}
+
+
+// Validate checks that 'dataURL' is a data URL; i.e., what the JSON Schema "format": "data-url"
+// (from older drafts of JSON Schema) means. It uses the default SchemaConfig.
+//
+// See SchemaConfig.Validate() for more.
+func Validate(dataURL string) error {
+	return SchemaConfig{}.Validate(dataURL)
+}
+
+
+// Validate checks that 'dataURL' is a data URL, and that it meets the requirements of the SchemaConfig.
+// It returns nil if it does.
+//
+// The errors it returns are the same kinds as the rest of this package:
+//
+//	NotADataUrlComplainer   — 'dataURL' is not a data URL
+//	SyntaxErrorComplainer   — 'dataURL' has a syntax error in it; or is not base64 encoded, when SchemaConfig.Base64Only is true
+//	BadMediaTypeComplainer  — the media type does not match SchemaConfig.MediaType
+//	TooLargeComplainer      — the contents is more than SchemaConfig.MaxDecodedSize bytes
+//	InternalErrorComplainer — SchemaConfig.MediaType is not a valid pattern
+//
+// Since its signature is func(string) error, it can be used as is, as a format (or keyword) checker
+// in a JSON Schema validator.
+//
+// Example usage:
+//
+//	config := dataurl.SchemaConfig{
+//		MediaType:      "image/*",
+//		MaxDecodedSize: 64 * 1024,
+//		Base64Only:     true,
+//	}
+//
+//	err := config.Validate(value)
+//	if nil != err {
+//		switch err.(type) {
+//		case dataurl.TooLargeComplainer:
+//			//@TODO
+//		case dataurl.BadRequestComplainer:
+//			//@TODO
+//		default:
+//			//@TODO
+//		}
+//	}
+func (config SchemaConfig) Validate(dataURL string) error {
+	pattern := strings.ToLower(strings.TrimSpace(config.MediaType))
+	if "" != pattern {
+		if _, err := path.Match(pattern, ""); nil != err {
+			return newInternalErrorComplainer("bad media type pattern %q: %s", config.MediaType, err)
+		}
+	}
+
+	if !strings.HasPrefix(dataURL, dataColon) {
+		return errNotADataUrl
+	}
+
+	// The size is checked before the contents are decoded; so that a data URL that is too large
+	// is rejected without decoding it. (If there isn't a comma, then parse() reports that.)
+	if index, base64Encoded := splitDataURL(dataURL); -1 != index {
+		if config.Base64Only && !base64Encoded {
+			return newSyntaxErrorComplainer("data URL is not base64 encoded")
+		}
+
+		if 0 < config.MaxDecodedSize {
+			var size int64
+			if base64Encoded {
+				size = base64DecodedLen(dataURL[index+len(semicolonBase64Comma):])
+			} else {
+				size = percentDecodedLen(dataURL[index+len(comma):])
+			}
+
+			if config.MaxDecodedSize < size {
+				return newTooLargeComplainer(config.MaxDecodedSize, "decoded content is %d bytes, which is more than %d bytes", size, config.MaxDecodedSize)
+			}
+		}
+	}
+
+	parcel, err := parse(dataURL)
+	if nil != err {
+		return err
+	}
+
+	if "" != pattern {
+		mimeType := essenceOf(parcel.MediaType())
+		if alias, ok := mediaTypeAliases[mimeType]; ok {
+			mimeType = alias
+		}
+
+		if matched, _ := path.Match(pattern, mimeType); !matched {
+			return newBadMediaTypeComplainer(fmt.Errorf("media type %q does not match %q", mimeType, pattern))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package dataurl
+
+
+import (
+	"testing"
+)
+
+
+func TestSchemaConfigValidate(t *testing.T) {
+
+	const png = `data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAA=`
+
+	tests := []struct{
+		Config   SchemaConfig
+		DataURL  string
+		Expected string
+	}{
+		{
+			Config:   SchemaConfig{},
+			DataURL:  `data:,Hello%20world!`,
+			Expected: "",
+		},
+		{
+			Config:   SchemaConfig{},
+			DataURL:  `https://example.com/`,
+			Expected: "not-a-data-url",
+		},
+		{
+			Config:   SchemaConfig{},
+			DataURL:  `data:image/png;base64,!!!!`,
+			Expected: "syntax-error",
+		},
+		{
+			Config:   SchemaConfig{MediaType: "image/png", MaxDecodedSize: 1024, Base64Only: true},
+			DataURL:  png,
+			Expected: "",
+		},
+		{
+			Config:   SchemaConfig{MediaType: "IMAGE/*"},
+			DataURL:  png,
+			Expected: "",
+		},
+		{
+			Config:   SchemaConfig{MediaType: "image/jpeg"},
+			DataURL:  `data:image/jpg;base64,/9j/4AAQ`,
+			Expected: "",
+		},
+		{
+			Config:   SchemaConfig{MediaType: "image/*"},
+			DataURL:  `data:text/plain;charset=utf-8,Hello`,
+			Expected: "bad-media-type",
+		},
+		{
+			Config:   SchemaConfig{MediaType: "text/plain"},
+			DataURL:  `data:,Hello`,
+			Expected: "",
+		},
+		{
+			Config:   SchemaConfig{Base64Only: true},
+			DataURL:  `data:,Hello`,
+			Expected: "syntax-error",
+		},
+		{
+			Config:   SchemaConfig{Base64Only: true},
+			DataURL:  `data:text/css,a{background:url(data:image/png;base64,AAAA)}`,
+			Expected: "syntax-error",
+		},
+		{
+			Config:   SchemaConfig{MaxDecodedSize: 5},
+			DataURL:  `data:,Hello`,
+			Expected: "",
+		},
+		{
+			Config:   SchemaConfig{MaxDecodedSize: 4},
+			DataURL:  `data:,Hello`,
+			Expected: "too-large",
+		},
+		{
+			Config:   SchemaConfig{MaxDecodedSize: 5},
+			DataURL:  `data:,%48%65%6C%6C%6F`,
+			Expected: "",
+		},
+		{
+			Config:   SchemaConfig{MaxDecodedSize: 4},
+			DataURL:  `data:,%48%65%6C%6C%6F`,
+			Expected: "too-large",
+		},
+		{
+			Config:   SchemaConfig{MaxDecodedSize: 5},
+			DataURL:  "data:;base64,SGVs\r\nbG8=",
+			Expected: "",
+		},
+		{
+			Config:   SchemaConfig{MaxDecodedSize: 4},
+			DataURL:  "data:;base64,SGVs\r\nbG8=",
+			Expected: "too-large",
+		},
+		{
+			// Too large is reported without decoding the contents (which would be a syntax error).
+			Config:   SchemaConfig{MaxDecodedSize: 8},
+			DataURL:  `data:;base64,!!!!!!!!!!!!!!!!`,
+			Expected: "too-large",
+		},
+		{
+			Config:   SchemaConfig{MediaType: "image/["},
+			DataURL:  png,
+			Expected: "internal-error",
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		err := test.Config.Validate(test.DataURL)
+
+		var actual string
+		switch err.(type) {
+		case nil:
+			actual = ""
+		case NotADataUrlComplainer:
+			actual = "not-a-data-url"
+		case SyntaxErrorComplainer:
+			actual = "syntax-error"
+		case BadMediaTypeComplainer:
+			actual = "bad-media-type"
+		case TooLargeComplainer:
+			actual = "too-large"
+		case InternalErrorComplainer:
+			actual = "internal-error"
+		default:
+			actual = "unknown"
+		}
+
+		if expected := test.Expected; expected != actual {
+			t.Errorf("For test #%d, expected %q, but actually got %q (%v).", testNumber, expected, actual, err)
+			t.Logf("CONFIG: %#v", test.Config)
+			t.Logf("DATA URL: %q", test.DataURL)
+			continue
+		}
+	}
+}
+
+
+func TestValidate(t *testing.T) {
+	if err := Validate(`data:,Hello`); nil != err {
+		t.Errorf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if err := Validate(`Hello`); nil == err {
+		t.Errorf("Expected an error, but did not actually get one.")
+	}
+}
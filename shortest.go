@@ -0,0 +1,132 @@
+package dataurl
+
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+
+// EncodedLen returns how long (in bytes) the data URL returned by dataurl.Encode() would
+// be, for the same 'mediaType', 'data' and 'encoding'; without creating the data URL.
+//
+// Example usage:
+//
+//	base64Len, err := dataurl.EncodedLen("image/svg+xml", svg, dataurl.EncodingBase64)
+//	if nil != err {
+//		//@TODO
+//	}
+//
+//	percentLen, err := dataurl.EncodedLen("image/svg+xml", svg, dataurl.EncodingPercent)
+//	if nil != err {
+//		//@TODO
+//	}
+func EncodedLen(mediaType string, data []byte, encoding Encoding) (int, error) {
+	mediaType, err := formatMediaType(mediaType)
+	if nil != err {
+		return 0, err
+	}
+
+	return encodedLen(mediaType, data, encoding)
+}
+
+
+// encodedLen is like EncodedLen, except that 'mediaType' must already be formatted.
+func encodedLen(mediaType string, data []byte, encoding Encoding) (int, error) {
+	length := len(dataColon) + len(mediaType)
+
+	switch encoding {
+	case EncodingBase64:
+		length += len(semicolonBase64Comma) + base64.StdEncoding.EncodedLen(len(data))
+	case EncodingPercent:
+		length += len(comma)
+		for _, b := range data {
+			if shouldPercentEncode(b) {
+				length += 3
+			} else {
+				length++
+			}
+		}
+	default:
+		return 0, newInternalErrorComplainer("Unknown encoding (%d) passed to dataurl.EncodedLen().", encoding)
+	}
+
+	return length, nil
+}
+
+
+// ShortestEncoding returns which encoding (base64 or percent encoding) results in
+// the shorter data URL for 'data'.
+//
+// Percent encoding is usually shorter for text (such as SVG and CSS), and base64
+// encoding is usually shorter for binary data. If they are the same length, then
+// base64 encoding is returned.
+func ShortestEncoding(data []byte) Encoding {
+	// The media type is the same either way, so it doesn't matter here.
+	base64Len, _  := encodedLen("", data, EncodingBase64)
+	percentLen, _ := encodedLen("", data, EncodingPercent)
+
+	if percentLen < base64Len {
+		return EncodingPercent
+	}
+
+	return EncodingBase64
+}
+
+
+// EncodeShortest is like dataurl.Encode(), except that it picks whichever encoding
+// results in the shorter data URL, and it minimizes the media type.
+//
+// Minimizing the media type drops anything that a data URL implies by default.
+// For example:
+//
+//	"text/plain;charset=US-ASCII" → ""
+//	"text/plain;charset=utf-8"    → ";charset=utf-8"
+//	"image/png;charset=US-ASCII"  → "image/png"
+//
+// (The "charset=US-ASCII" parameter is what dataurl.Parse() adds when a data URL does
+// not have a charset, so passing the result of Parcel.MediaType() will not lengthen
+// the data URL.)
+//
+// Example usage:
+//
+//	dataURL, err := dataurl.EncodeShortest(parcel.MediaType(), parcel.Bytes())
+//	if nil != err {
+//		//@TODO
+//	}
+func EncodeShortest(mediaType string, data []byte) (string, error) {
+	mediaType, err := minimizeMediaType(mediaType)
+	if nil != err {
+		return "", err
+	}
+
+	return encodeFormatted(mediaType, data, ShortestEncoding(data))
+}
+
+
+// minimizeMediaType is like formatMediaType, except that it also drops whatever a
+// data URL implies by default. (See EncodeShortest.)
+func minimizeMediaType(mediaType string) (string, error) {
+	if "" == mediaType {
+		return "", nil
+	}
+
+	formatted, err := rewriteMediaType(mediaType, func(mimeType string, params map[string]string) string {
+		if charset, ok := params["charset"]; ok && strings.EqualFold("US-ASCII", charset) {
+			delete(params, "charset")
+		}
+		return mimeType
+	})
+	if nil != err {
+		return "", err
+	}
+
+	if strings.HasPrefix(formatted, "text/plain;") {
+		formatted = formatted[len("text/plain"):]
+	}
+	if "text/plain" == formatted {
+		formatted = ""
+	}
+
+	return formatted, nil
+}
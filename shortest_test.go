@@ -0,0 +1,119 @@
+package dataurl
+
+
+import (
+	"bytes"
+	"testing"
+)
+
+
+func TestEncodedLen(t *testing.T) {
+
+	tests := []struct{
+		MediaType string
+		Data      []byte
+	}{
+		{
+			MediaType: "",
+			Data:      []byte(""),
+		},
+		{
+			MediaType: "",
+			Data:      []byte("A brief note"),
+		},
+		{
+			MediaType: "TEXT/Plain; Charset=utf-8",
+			Data:      []byte("1+1=2 & 2+2=4\r\n"),
+		},
+		{
+			MediaType: "image/svg+xml",
+			Data:      []byte(`<svg xmlns="http://www.w3.org/2000/svg"><circle r="5"/></svg>`),
+		},
+		{
+			MediaType: "application/octet-stream",
+			Data:      []byte("\x00\x01\x02\xfd\xfe\xff"),
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		for _, encoding := range []Encoding{EncodingBase64, EncodingPercent} {
+			dataURL, err := Encode(test.MediaType, test.Data, encoding)
+			if nil != err {
+				t.Errorf("For test #%d and encoding %s, did not expect an error, but actually got one: %v", testNumber, encoding, err)
+				continue
+			}
+
+			length, err := EncodedLen(test.MediaType, test.Data, encoding)
+			if nil != err {
+				t.Errorf("For test #%d and encoding %s, did not expect an error, but actually got one: %v", testNumber, encoding, err)
+				continue
+			}
+
+			if expected, actual := len(dataURL), length; expected != actual {
+				t.Errorf("For test #%d and encoding %s, expected length to be %d, but actually was %d.\nData URL: %q", testNumber, encoding, expected, actual, dataURL)
+				continue
+			}
+		}
+	}
+}
+
+
+func TestEncodeShortest(t *testing.T) {
+
+	tests := []struct{
+		MediaType string
+		Data      []byte
+		Expected  string
+	}{
+		{
+			MediaType: "text/plain;charset=US-ASCII",
+			Data:      []byte("Hello"),
+			Expected:  `data:,Hello`,
+		},
+		{
+			MediaType: "text/plain;charset=utf-8",
+			Data:      []byte("Hello"),
+			Expected:  `data:;charset=utf-8,Hello`,
+		},
+		{
+			MediaType: "image/svg+xml;charset=us-ascii",
+			Data:      []byte(`<svg/>`),
+			Expected:  `data:image/svg+xml,%3Csvg/%3E`,
+		},
+		{
+			MediaType: "image/png;charset=US-ASCII",
+			Data:      []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00"),
+			Expected:  `data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAA=`,
+		},
+		{
+			MediaType: "",
+			Data:      []byte(""),
+			Expected:  `data:,`,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		actual, err := EncodeShortest(test.MediaType, test.Data)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if expected := test.Expected; expected != actual {
+			t.Errorf("For test #%d, expected data URL to be %q, but actually was %q.", testNumber, expected, actual)
+			continue
+		}
+
+		parcel, err := Parse(actual)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error when parsing, but actually got one: %v", testNumber, err)
+			continue
+		}
+		if !bytes.Equal(test.Data, parcel.Bytes()) {
+			t.Errorf("For test #%d, expected content to be %q, but actually was %q.", testNumber, test.Data, parcel.Bytes())
+			continue
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package sourcemapurl
+
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+var (
+	errNoComment = errors.New("sourcemapurl: no sourceMappingURL comment")
+)
+
+
+// commentPattern matches a sourceMappingURL comment, on a line by itself.
+//
+// The 1st sub-match is how the comment starts ("//" or "/*"); the 2nd is the URL; and the 3rd is the
+// "*/" that ends the comment (if there is one).
+//
+// The (older) "@" form of the comment (ex: "//@ sourceMappingURL=...") is matched too.
+var commentPattern = regexp.MustCompile(`(?m)^[ \t]*(//|/\*)[#@][ \t]+sourceMappingURL=(\S+?)[ \t]*(\*/)?[ \t]*\r?$`)
+
+
+// Syntax is the syntax of a sourceMappingURL comment.
+type Syntax int
+
+
+const (
+	// SyntaxJS is a JavaScript comment. For example: //# sourceMappingURL=...
+	SyntaxJS Syntax = iota
+
+	// SyntaxCSS is a CSS comment. For example: /*# sourceMappingURL=... */
+	SyntaxCSS
+)
+
+
+// String returns the name of the syntax.
+func (syntax Syntax) String() string {
+	switch syntax {
+	case SyntaxJS:
+		return "js"
+	case SyntaxCSS:
+		return "css"
+	default:
+		return "unknown"
+	}
+}
+
+
+// Comment is a sourceMappingURL comment in a JavaScript or CSS file.
+type Comment struct {
+	// Start and End are where (in the file) the line with the comment on it is; not including
+	// its line break.
+	Start int
+	End   int
+
+	// URL is the URL of the source map. It is a data URL if the source map is inline.
+	URL string
+
+	Syntax Syntax
+}
+
+
+// Find returns the (last) sourceMappingURL comment in the JavaScript or CSS file 'source'. It returns
+// false if there isn't one.
+//
+// The comment has to be on a line by itself. (Which is where bundlers put it.)
+func Find(source []byte) (Comment, bool) {
+	for _, loc := range reverse(commentPattern.FindAllSubmatchIndex(source, -1)) {
+		opening := string(source[loc[2]:loc[3]])
+		closed := -1 != loc[6]
+
+		var syntax Syntax
+		switch {
+		case "//" == opening && !closed:
+			syntax = SyntaxJS
+		case "/*" == opening && closed:
+			syntax = SyntaxCSS
+		default:
+			continue
+		}
+
+		end := loc[1]
+		if loc[0] < end && '\r' == source[end-1] {
+			end--
+		}
+
+		return Comment{
+			Start:  loc[0],
+			End:    end,
+			URL:    string(source[loc[4]:loc[5]]),
+			Syntax: syntax,
+		}, true
+	}
+
+	return Comment{}, false
+}
+
+
+// Extract returns the inline source map in the JavaScript or CSS file 'source'.
+//
+// It returns an error if there is no sourceMappingURL comment; or if its URL is not a data URL (i.e.,
+// the source map is in a file of its own).
+func Extract(source []byte) (*SourceMap, error) {
+	comment, found := Find(source)
+	if !found {
+		return nil, errNoComment
+	}
+
+	parcel, err := dataurl.Parse(comment.URL)
+	if nil != err {
+		return nil, fmt.Errorf("sourcemapurl: source map is not inline: %w", err)
+	}
+
+	return Decode(parcel)
+}
+
+
+// Strip returns 'source' without its sourceMappingURL comment (and the line break after it). If there
+// isn't one, then 'source' is returned as is.
+//
+// It does not matter whether the source map is inline or not.
+func Strip(source []byte) []byte {
+	comment, found := Find(source)
+	if !found {
+		return source
+	}
+
+	end := comment.End
+	switch {
+	case bytes.HasPrefix(source[end:], []byte("\r\n")):
+		end += 2
+	case bytes.HasPrefix(source[end:], []byte("\n")):
+		end++
+	}
+
+	stripped := make([]byte, 0, len(source) - (end - comment.Start))
+	stripped = append(stripped, source[:comment.Start]...)
+	stripped = append(stripped, source[end:]...)
+
+	return stripped
+}
+
+
+// Inline returns 'source' with 'sourceMap' inlined into it; replacing its sourceMappingURL comment,
+// if it has one, or appending one to the end of it, if it doesn't.
+//
+// The comment is written in the syntax 'syntax'.
+func Inline(source []byte, sourceMap *SourceMap, syntax Syntax) ([]byte, error) {
+	dataURL, err := Encode(sourceMap)
+	if nil != err {
+		return nil, err
+	}
+
+	var line string
+	switch syntax {
+	case SyntaxJS:
+		line = "//# sourceMappingURL=" + dataURL
+	case SyntaxCSS:
+		line = "/*# sourceMappingURL=" + dataURL + " */"
+	default:
+		return nil, fmt.Errorf("sourcemapurl: unknown syntax (%d)", syntax)
+	}
+
+	var buffer bytes.Buffer
+
+	if comment, found := Find(source); found {
+		buffer.Write(source[:comment.Start])
+		buffer.WriteString(line)
+		buffer.Write(source[comment.End:])
+		return buffer.Bytes(), nil
+	}
+
+	buffer.Write(source)
+	if 0 < len(source) && '\n' != source[len(source)-1] {
+		buffer.WriteByte('\n')
+	}
+	buffer.WriteString(line)
+	buffer.WriteByte('\n')
+
+	return buffer.Bytes(), nil
+}
+
+
+// reverse returns 'locs' in reverse order.
+func reverse(locs [][]int) [][]int {
+	for i, j := 0, len(locs)-1; i < j; i, j = i+1, j-1 {
+		locs[i], locs[j] = locs[j], locs[i]
+	}
+
+	return locs
+}
@@ -0,0 +1,172 @@
+package sourcemapurl
+
+
+import (
+	"testing"
+)
+
+
+const inlineMap = `data:application/json;charset=utf-8;base64,eyJ2ZXJzaW9uIjozLCJzb3VyY2VzIjpbImEuanMiXSwibmFtZXMiOltdLCJtYXBwaW5ncyI6IkFBQUEifQ==`
+
+
+func TestFind(t *testing.T) {
+
+	tests := []struct{
+		Source         string
+		ExpectedFound  bool
+		ExpectedURL    string
+		ExpectedSyntax Syntax
+		ExpectedLine   string
+	}{
+		{
+			Source:         "alert(1);\n//# sourceMappingURL=bundle.js.map\n",
+			ExpectedFound:  true,
+			ExpectedURL:    "bundle.js.map",
+			ExpectedSyntax: SyntaxJS,
+			ExpectedLine:   "//# sourceMappingURL=bundle.js.map",
+		},
+		{
+			Source:         "a{color:red}\r\n/*# sourceMappingURL=" + inlineMap + " */\r\n",
+			ExpectedFound:  true,
+			ExpectedURL:    inlineMap,
+			ExpectedSyntax: SyntaxCSS,
+			ExpectedLine:   "/*# sourceMappingURL=" + inlineMap + " */",
+		},
+		{
+			Source:         "a{color:red}\n/*# sourceMappingURL=a.css.map*/",
+			ExpectedFound:  true,
+			ExpectedURL:    "a.css.map",
+			ExpectedSyntax: SyntaxCSS,
+			ExpectedLine:   "/*# sourceMappingURL=a.css.map*/",
+		},
+		{
+			Source:         "//# sourceMappingURL=first.map\nalert(1);\n//@ sourceMappingURL=last.map",
+			ExpectedFound:  true,
+			ExpectedURL:    "last.map",
+			ExpectedSyntax: SyntaxJS,
+			ExpectedLine:   "//@ sourceMappingURL=last.map",
+		},
+		{
+			Source:        "var s = '//# sourceMappingURL=nope.map';\n",
+			ExpectedFound: false,
+		},
+		{
+			Source:        "//# sourceMappingURL=broken.map */\n",
+			ExpectedFound: false,
+		},
+	}
+
+	for testNumber, test := range tests {
+
+		comment, found := Find([]byte(test.Source))
+
+		if expected, actual := test.ExpectedFound, found; expected != actual {
+			t.Errorf("For test #%d, expected found to be %t, but actually got %t.", testNumber, expected, actual)
+			continue
+		}
+		if !found {
+			continue
+		}
+
+		if expected, actual := test.ExpectedURL, comment.URL; expected != actual {
+			t.Errorf("For test #%d, expected URL %q, but actually got %q.", testNumber, expected, actual)
+		}
+		if expected, actual := test.ExpectedSyntax, comment.Syntax; expected != actual {
+			t.Errorf("For test #%d, expected syntax %s, but actually got %s.", testNumber, expected, actual)
+		}
+		if expected, actual := test.ExpectedLine, test.Source[comment.Start:comment.End]; expected != actual {
+			t.Errorf("For test #%d, expected line %q, but actually got %q.", testNumber, expected, actual)
+		}
+	}
+}
+
+
+func TestExtract(t *testing.T) {
+	sourceMap, err := Extract([]byte("alert(1);\n//# sourceMappingURL=" + inlineMap + "\n"))
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if expected, actual := "AAAA", sourceMap.Mappings; expected != actual {
+		t.Errorf("Expected mappings %q, but actually got %q.", expected, actual)
+	}
+
+	if _, err := Extract([]byte("alert(1);\n//# sourceMappingURL=bundle.js.map\n")); nil == err {
+		t.Errorf("Expected an error for a source map that is not inline, but did not actually get one.")
+	}
+	if _, err := Extract([]byte("alert(1);\n")); nil == err {
+		t.Errorf("Expected an error for no source map, but did not actually get one.")
+	}
+}
+
+
+func TestStrip(t *testing.T) {
+
+	tests := []struct{
+		Source   string
+		Expected string
+	}{
+		{
+			Source:   "alert(1);\n//# sourceMappingURL=" + inlineMap + "\n",
+			Expected: "alert(1);\n",
+		},
+		{
+			Source:   "a{}\r\n/*# sourceMappingURL=a.css.map */\r\nb{}\r\n",
+			Expected: "a{}\r\nb{}\r\n",
+		},
+		{
+			Source:   "alert(1);\n",
+			Expected: "alert(1);\n",
+		},
+	}
+
+	for testNumber, test := range tests {
+		if expected, actual := test.Expected, string(Strip([]byte(test.Source))); expected != actual {
+			t.Errorf("For test #%d, expected %q, but actually got %q.", testNumber, expected, actual)
+		}
+	}
+}
+
+
+func TestInline(t *testing.T) {
+	sourceMap := &SourceMap{
+		Version:  3,
+		Sources:  []string{"a.js"},
+		Names:    []string{},
+		Mappings: "AAAA",
+	}
+
+	tests := []struct{
+		Source   string
+		Syntax   Syntax
+		Expected string
+	}{
+		{
+			Source:   "alert(1);",
+			Syntax:   SyntaxJS,
+			Expected: "alert(1);\n//# sourceMappingURL=" + inlineMap + "\n",
+		},
+		{
+			Source:   "alert(1);\n//# sourceMappingURL=bundle.js.map\r\n",
+			Syntax:   SyntaxJS,
+			Expected: "alert(1);\n//# sourceMappingURL=" + inlineMap + "\r\n",
+		},
+		{
+			Source:   "a{}\n",
+			Syntax:   SyntaxCSS,
+			Expected: "a{}\n/*# sourceMappingURL=" + inlineMap + " */\n",
+		},
+	}
+
+	for testNumber, test := range tests {
+		actual, err := Inline([]byte(test.Source), sourceMap, test.Syntax)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if expected := test.Expected; expected != string(actual) {
+			t.Errorf("For test #%d, expected %q, but actually got %q.", testNumber, expected, string(actual))
+		}
+	}
+}
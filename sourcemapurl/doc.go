@@ -0,0 +1,35 @@
+// Package sourcemapurl finds, decodes, writes and strips inline source maps in JavaScript and CSS files.
+//
+// Bundlers (and minifiers, and compilers) put a source map into the file it is for, as a data URL in
+// a comment at the end of the file:
+//
+//	//# sourceMappingURL=data:application/json;charset=utf-8;base64,eyJ2ZXJzaW9uIjozLC...
+//
+//	/*# sourceMappingURL=data:application/json;charset=utf-8;base64,eyJ2ZXJzaW9uIjozLC... */
+//
+// The first is how it is done in JavaScript; and the second is how it is done in CSS.
+//
+// Example Usage
+//
+//	source, err := os.ReadFile("bundle.js")
+//	if nil != err {
+//		//@TODO
+//	}
+//
+//	sourceMap, err := sourcemapurl.Extract(source)
+//	if nil != err {
+//		//@TODO
+//	}
+//
+//	fmt.Println(sourceMap.Sources)
+//
+//	// Remove the inline source map; and save it to its own file instead.
+//	source = sourcemapurl.Strip(source)
+//
+// Another Example Usage
+//
+//	source, err = sourcemapurl.Inline(source, sourceMap, sourcemapurl.SyntaxJS)
+//	if nil != err {
+//		//@TODO
+//	}
+package sourcemapurl
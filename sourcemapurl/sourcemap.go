@@ -0,0 +1,115 @@
+package sourcemapurl
+
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+// mediaType is the media type of the data URLs that sourcemapurl.Encode() returns. (It is what
+// bundlers use.)
+const mediaType = "application/json;charset=utf-8"
+
+
+var (
+	errNilSourceMap = errors.New("sourcemapurl: nil source map")
+)
+
+
+// SourceMap is a source map; as defined by version 3 of the Source Map specification.
+type SourceMap struct {
+	Version    int    `json:"version"`
+	File       string `json:"file,omitempty"`
+	SourceRoot string `json:"sourceRoot,omitempty"`
+
+	Sources []string `json:"sources"`
+
+	// SourcesContent has the contents of each of Sources (in the same order). An element is nil
+	// if the contents of that source is not included.
+	SourcesContent []*string `json:"sourcesContent,omitempty"`
+
+	Names    []string `json:"names"`
+	Mappings string   `json:"mappings"`
+
+	// IgnoreList has the indexes (into Sources) of the sources that debuggers should skip over.
+	// (Ex: third-party libraries.)
+	IgnoreList []int `json:"ignoreList,omitempty"`
+
+	// Sections is only used by an index map; which (rather than having its own Sources, Names and
+	// Mappings) is made up of other source maps.
+	Sections []Section `json:"sections,omitempty"`
+}
+
+
+// Section is a section of an index map.
+type Section struct {
+	Offset Offset     `json:"offset"`
+	Map    *SourceMap `json:"map"`
+}
+
+
+// Offset is where (in the generated file) a Section starts. Both are zero-based.
+type Offset struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+
+// Decode decodes the source map that is the contents of 'parcel'.
+//
+// The media type of 'parcel' must be "application/json" (with any parameters); and the source map must
+// be version 3.
+func Decode(parcel dataurl.Parcel) (*SourceMap, error) {
+	mimeType, _, err := mime.ParseMediaType(parcel.MediaType())
+	if nil != err {
+		return nil, fmt.Errorf("sourcemapurl: bad media type %q: %w", parcel.MediaType(), err)
+	}
+	if "application/json" != mimeType {
+		return nil, fmt.Errorf("sourcemapurl: expected the media type of a source map to be %q, but it is %q", "application/json", mimeType)
+	}
+
+	var sourceMap SourceMap
+	if err := json.Unmarshal(dataurl.UnsafeBytes(parcel), &sourceMap); nil != err {
+		return nil, fmt.Errorf("sourcemapurl: could not decode source map: %w", err)
+	}
+
+	if 3 != sourceMap.Version {
+		return nil, fmt.Errorf("sourcemapurl: source map version %d is not supported", sourceMap.Version)
+	}
+
+	return &sourceMap, nil
+}
+
+
+// Encode returns 'sourceMap' as a (base64 encoded) data URL, with the media type "application/json;charset=utf-8".
+//
+// Example usage:
+//
+//	dataURL, err := sourcemapurl.Encode(sourceMap)
+//	if nil != err {
+//		//@TODO
+//	}
+//
+//	fmt.Println(dataURL) // data:application/json;charset=utf-8;base64,eyJ2ZXJzaW9uIjozLC...
+func Encode(sourceMap *SourceMap) (string, error) {
+	if nil == sourceMap {
+		return "", errNilSourceMap
+	}
+
+	var buffer bytes.Buffer
+
+	// Source maps often have HTML in their "sourcesContent"; which there is no need to escape.
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(sourceMap); nil != err {
+		return "", fmt.Errorf("sourcemapurl: could not encode source map: %w", err)
+	}
+
+	return dataurl.Encode(mediaType, bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), dataurl.EncodingBase64)
+}
@@ -0,0 +1,62 @@
+package sourcemapurl
+
+
+import (
+	"testing"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+func TestEncodeDecode(t *testing.T) {
+	content := "<p>Hello</p>"
+
+	sourceMap := &SourceMap{
+		Version:        3,
+		File:           "bundle.js",
+		Sources:        []string{"a.js", "b.js"},
+		SourcesContent: []*string{&content, nil},
+		Names:          []string{},
+		Mappings:       "AAAA",
+	}
+
+	dataURL, err := Encode(sourceMap)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	parcel, err := dataurl.Parse(dataURL)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if expected, actual := `{"version":3,"file":"bundle.js","sources":["a.js","b.js"],"sourcesContent":["<p>Hello</p>",null],"names":[],"mappings":"AAAA"}`, parcel.String(); expected != actual {
+		t.Errorf("Expected %s, but actually got %s.", expected, actual)
+	}
+
+	decoded, err := Decode(parcel)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+	if expected, actual := 2, len(decoded.SourcesContent); expected != actual {
+		t.Fatalf("Expected %d sourcesContent, but actually got %d.", expected, actual)
+	}
+	if nil == decoded.SourcesContent[0] || content != *decoded.SourcesContent[0] || nil != decoded.SourcesContent[1] {
+		t.Errorf("Expected sourcesContent to be decoded as it was encoded, but it was not: %v", decoded.SourcesContent)
+	}
+}
+
+
+func TestDecodeErrors(t *testing.T) {
+	dataURLs := []string{
+		`data:text/plain,{"version":3}`,
+		`data:application/json,{"version":2}`,
+		`data:application/json,{`,
+	}
+
+	for testNumber, dataURL := range dataURLs {
+		if _, err := Decode(dataurl.MustParse(dataURL)); nil == err {
+			t.Errorf("For test #%d, expected an error, but did not actually get one.", testNumber)
+		}
+	}
+}
@@ -46,23 +46,59 @@ type SyntaxErrorComplainer interface {
 // internalSyntaxErrorComplainer is the only underlying implementation that fits the
 // SyntaxErrorComplainer interface, in this library.
 type internalSyntaxErrorComplainer struct {
-	msg string
+	msg    string
+	offset int
 }
 
 
 // newSyntaxErrorComplainer creates a new internalSyntaxErrorComplainer (struct) and
 // returns it as a SyntaxErrorComplainer (interface).
+//
+// Where the syntax error is, is not known. (See newSyntaxErrorComplainerAt().)
 func newSyntaxErrorComplainer(format string, a ...interface{}) SyntaxErrorComplainer {
+	return newSyntaxErrorComplainerAt(-1, format, a...)
+}
+
+
+// newSyntaxErrorComplainerAt is like newSyntaxErrorComplainer(), except that it also
+// records where (as a byte offset into the data URL) the syntax error is.
+func newSyntaxErrorComplainerAt(offset int, format string, a ...interface{}) SyntaxErrorComplainer {
 	msg := fmt.Sprintf(format, a...)
 
 	err := internalSyntaxErrorComplainer{
 		msg:msg,
+		offset:offset,
 	}
 
 	return &err
 }
 
 
+// SyntaxErrorOffset returns where (as a byte offset into the data URL) the syntax error
+// 'err' is.
+//
+// It returns false if 'err' is not a SyntaxErrorComplainer from this library, or if where
+// the syntax error is, is not known.
+//
+// Example usage:
+//
+//	parcel, err := dataurl.Parse(dataURL)
+//	if nil != err {
+//		if offset, ok := dataurl.SyntaxErrorOffset(err); ok {
+//			fmt.Printf("syntax error at byte %d: %s\n", offset, err)
+//		}
+//		//@TODO
+//	}
+func SyntaxErrorOffset(err error) (int, bool) {
+	complainer, ok := err.(*internalSyntaxErrorComplainer)
+	if !ok || complainer.offset < 0 {
+		return 0, false
+	}
+
+	return complainer.offset, true
+}
+
+
 // Error method is necessary to satisfy the 'error' interface (and the
 // SyntaxErrorComplainer interface).
 func (err *internalSyntaxErrorComplainer) Error() string {
@@ -0,0 +1,245 @@
+package dataurl
+
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+
+// DefaultAllowedMediaTypes is the media type allow-list used by dataurl.FuncMap(), and by a
+// TemplateConfig that does not have its own.
+//
+// Note that "image/svg+xml" is not on it, since an SVG image can contain scripts. And neither
+// is "text/html".
+var DefaultAllowedMediaTypes = []string{
+	"image/avif",
+	"image/bmp",
+	"image/gif",
+	"image/jpeg",
+	"image/png",
+	"image/webp",
+	"image/x-icon",
+	"font/*",
+	"audio/*",
+	"video/*",
+	"text/plain",
+	"text/css",
+}
+
+
+// TemplateConfig is used to configure the template functions returned by TemplateConfig.FuncMap().
+type TemplateConfig struct {
+	// AllowedMediaTypes is the allow-list of media types that the template functions will
+	// create data URLs for. Each is either an exact media type (ex: "image/png"), or has a
+	// wildcard subtype (ex: "image/*").
+	//
+	// If nil, then DefaultAllowedMediaTypes is used.
+	AllowedMediaTypes []string
+
+	// FS is what the "dataurlFile" template function reads files from.
+	// If nil, then "dataurlFile" always returns an error.
+	FS fs.FS
+}
+
+
+// FuncMap returns template functions that create data URLs, using the default TemplateConfig.
+//
+// See TemplateConfig.FuncMap() for the template functions.
+func FuncMap() template.FuncMap {
+	return TemplateConfig{}.FuncMap()
+}
+
+
+// FuncMap returns template functions that create data URLs.
+//
+// html/template does not trust data URLs (except for some image types), and replaces them with
+// "#ZgotmplZ". The template functions return values typed so that html/template trusts them;
+// but only after checking the media type against the allow-list in the TemplateConfig.
+//
+// The template functions are:
+//
+//	dataurl       MEDIATYPE DATA     → template.URL    (ex: <a href="{{dataurl "text/plain" .Notes}}">)
+//	dataurlFile   NAME               → template.URL    (ex: <img src="{{dataurlFile "logo.png"}}">)
+//	dataurlImg    VALUE              → template.URL    (ex: <img src="{{dataurlImg .Avatar}}">)
+//	dataurlSrcset VALUE DESCRIPTOR…  → template.Srcset (ex: <img srcset="{{dataurlSrcset .Small "1x" .Large "2x"}}">)
+//	dataurlCSS    VALUE              → template.CSS    (ex: <div style="background-image: {{dataurlCSS .Avatar}}">)
+//
+// Where DATA is a string or a []byte; and VALUE is a Parcel, a data URL (string), or the
+// contents of an image ([]byte). dataurlImg and dataurlSrcset only accept images.
+//
+// The map can be passed to both html/template and (after a conversion) text/template:
+//
+//	tmpl := htmltemplate.New("page").Funcs(config.FuncMap())
+//
+//	tmpl := texttemplate.New("page").Funcs(texttemplate.FuncMap(config.FuncMap()))
+func (config TemplateConfig) FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"dataurl":       config.dataurl,
+		"dataurlFile":   config.dataurlFile,
+		"dataurlImg":    config.dataurlImg,
+		"dataurlSrcset": config.dataurlSrcset,
+		"dataurlCSS":    config.dataurlCSS,
+	}
+}
+
+
+func (config TemplateConfig) allowed(mediaType string) error {
+	mimeType := essenceOf(mediaType)
+	if alias, ok := mediaTypeAliases[mimeType]; ok {
+		mimeType = alias
+	}
+
+	allowList := config.AllowedMediaTypes
+	if nil == allowList {
+		allowList = DefaultAllowedMediaTypes
+	}
+
+	for _, pattern := range allowList {
+		if matched, err := path.Match(strings.ToLower(pattern), mimeType); nil == err && matched {
+			return nil
+		}
+	}
+
+	return newBadMediaTypeComplainer(fmt.Errorf("media type %q is not allowed in templates", mimeType))
+}
+
+
+// encodeAllowed creates a data URL; if the media type is on the allow-list.
+func (config TemplateConfig) encodeAllowed(mediaType string, data []byte) (string, error) {
+	if err := config.allowed(mediaType); nil != err {
+		return "", err
+	}
+
+	return EncodeShortest(mediaType, data)
+}
+
+
+// parcelOf turns 'value' (a Parcel, a data URL, or the contents of an image) into a Parcel.
+func parcelOf(value interface{}) (Parcel, error) {
+	switch casted := value.(type) {
+	case Parcel:
+		return casted, nil
+	case string:
+		return Parse(casted)
+	case template.URL:
+		return Parse(string(casted))
+	case []byte:
+		parcel := newParcel()
+		parcel.mediaType = GuessMediaType("", casted)
+		parcel.content = string(casted)
+		return parcel, nil
+	default:
+		return nil, newBadRequestComplainer("cannot create a data URL from a value of type %T", value)
+	}
+}
+
+
+func (config TemplateConfig) dataurl(mediaType string, data interface{}) (template.URL, error) {
+	var p []byte
+	switch casted := data.(type) {
+	case []byte:
+		p = casted
+	case string:
+		p = []byte(casted)
+	default:
+		return "", newBadRequestComplainer("cannot create a data URL from a value of type %T", data)
+	}
+
+	dataURL, err := config.encodeAllowed(mediaType, p)
+	if nil != err {
+		return "", err
+	}
+
+	return template.URL(dataURL), nil
+}
+
+
+func (config TemplateConfig) dataurlFile(name string) (template.URL, error) {
+	if nil == config.FS {
+		return "", newBadRequestComplainer("no file system configured for the dataurlFile template function")
+	}
+
+	data, err := fs.ReadFile(config.FS, name)
+	if nil != err {
+		return "", err
+	}
+
+	dataURL, err := config.encodeAllowed(GuessMediaType(name, data), data)
+	if nil != err {
+		return "", err
+	}
+
+	return template.URL(dataURL), nil
+}
+
+
+func (config TemplateConfig) image(value interface{}) (string, error) {
+	parcel, err := parcelOf(value)
+	if nil != err {
+		return "", err
+	}
+
+	if !strings.HasPrefix(essenceOf(parcel.MediaType()), "image/") {
+		return "", newBadMediaTypeComplainer(fmt.Errorf("media type %q is not an image", parcel.MediaType()))
+	}
+
+	return config.encodeAllowed(parcel.MediaType(), UnsafeBytes(parcel))
+}
+
+
+func (config TemplateConfig) dataurlImg(value interface{}) (template.URL, error) {
+	dataURL, err := config.image(value)
+	if nil != err {
+		return "", err
+	}
+
+	return template.URL(dataURL), nil
+}
+
+
+func (config TemplateConfig) dataurlSrcset(args ...interface{}) (template.Srcset, error) {
+	if 0 != len(args)%2 {
+		return "", newBadRequestComplainer("dataurlSrcset expects pairs of values and descriptors, but got %d arguments", len(args))
+	}
+
+	var candidates []string
+	for i := 0; i < len(args); i += 2 {
+		dataURL, err := config.image(args[i])
+		if nil != err {
+			return "", err
+		}
+
+		descriptor := fmt.Sprint(args[i+1])
+		if strings.ContainsAny(descriptor, ", \t\r\n") {
+			return "", newBadRequestComplainer("bad srcset descriptor %q", descriptor)
+		}
+
+		// A comma inside of a URL in a srcset is OK. But a trailing one is not.
+		if strings.HasSuffix(dataURL, ",") {
+			return "", newBadRequestComplainer("cannot put an empty data URL in a srcset")
+		}
+
+		candidates = append(candidates, dataURL + " " + descriptor)
+	}
+
+	return template.Srcset(strings.Join(candidates, ", ")), nil
+}
+
+
+func (config TemplateConfig) dataurlCSS(value interface{}) (template.CSS, error) {
+	parcel, err := parcelOf(value)
+	if nil != err {
+		return "", err
+	}
+
+	dataURL, err := config.encodeAllowed(parcel.MediaType(), UnsafeBytes(parcel))
+	if nil != err {
+		return "", err
+	}
+
+	return template.CSS(`url("` + Escape(dataURL, ContextCSSURL) + `")`), nil
+}
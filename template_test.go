@@ -0,0 +1,159 @@
+package dataurl
+
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"testing/fstest"
+	texttemplate "text/template"
+)
+
+
+func TestFuncMap(t *testing.T) {
+	const png = "\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00"
+
+	config := TemplateConfig{
+		FS: fstest.MapFS{
+			"logo.png":  {Data: []byte(png)},
+			"page.html": {Data: []byte("<p>Hi</p>")},
+		},
+	}
+
+	tests := []struct{
+		Template string
+		Data     interface{}
+		Expected string
+	}{
+		{
+			Template: `<img src="{{dataurlImg .}}">`,
+			Data:     "data:image/png;base64," + "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAA=",
+			Expected: `<img src="data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAA=">`,
+		},
+		{
+			Template: `<img src="{{dataurlImg .}}">`,
+			Data:     []byte(png),
+			Expected: `<img src="data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAA=">`,
+		},
+		{
+			Template: `<img src="{{dataurlFile "logo.png"}}">`,
+			Expected: `<img src="data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAA=">`,
+		},
+		{
+			Template: `<a href="{{dataurl "text/plain" .}}">notes</a>`,
+			Data:     "Hello world!",
+			Expected: `<a href="data:,Hello%20world!">notes</a>`,
+		},
+		{
+			Template: `<img srcset="{{dataurlSrcset . "1x" . "2x"}}">`,
+			Data:     []byte(png),
+			Expected: `<img srcset="data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAA= 1x, data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAA= 2x">`,
+		},
+		{
+			Template: `<div style="background-image: {{dataurlCSS .}}"></div>`,
+			Data:     []byte(png),
+			Expected: `<div style="background-image: url(&#34;data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAA=&#34;)"></div>`,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		tmpl, err := htmltemplate.New("test").Funcs(config.FuncMap()).Parse(test.Template)
+		if nil != err {
+			t.Errorf("For test #%d, did not expect an error when parsing the template, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		var buffer bytes.Buffer
+		if err := tmpl.Execute(&buffer, test.Data); nil != err {
+			t.Errorf("For test #%d, did not expect an error when executing the template, but actually got one: %v", testNumber, err)
+			continue
+		}
+
+		if expected, actual := test.Expected, buffer.String(); expected != actual {
+			t.Errorf("For test #%d, expected %q, but actually got %q.", testNumber, expected, actual)
+			continue
+		}
+	}
+}
+
+
+func TestFuncMapFail(t *testing.T) {
+
+	tests := []struct{
+		Config   TemplateConfig
+		Template string
+		Data     interface{}
+	}{
+		{
+			Template: `<a href="{{dataurl "text/html" .}}">x</a>`,
+			Data:     "<script>alert(1)</script>",
+		},
+		{
+			Template: `<img src="{{dataurlImg .}}">`,
+			Data:     `data:image/svg+xml,<svg onload="alert(1)"/>`,
+		},
+		{
+			Template: `<img src="{{dataurlImg .}}">`,
+			Data:     `data:text/plain,hello`,
+		},
+		{
+			Template: `<img src="{{dataurlFile "logo.png"}}">`,
+		},
+		{
+			Config:   TemplateConfig{AllowedMediaTypes: []string{"image/gif"}},
+			Template: `<img src="{{dataurlImg .}}">`,
+			Data:     `data:image/png;base64,iVBORw0KGgo=`,
+		},
+	}
+
+
+	for testNumber, test := range tests {
+		tmpl := htmltemplate.Must(htmltemplate.New("test").Funcs(test.Config.FuncMap()).Parse(test.Template))
+
+		var buffer bytes.Buffer
+		if err := tmpl.Execute(&buffer, test.Data); nil == err {
+			t.Errorf("For test #%d, expected an error, but actually did not get one: %q", testNumber, buffer.String())
+			continue
+		}
+	}
+}
+
+
+func TestFuncMapBadRequest(t *testing.T) {
+	var config TemplateConfig
+
+	const png = `data:image/png;base64,iVBORw0KGgo=`
+
+	_, err0 := parcelOf(42)
+	_, err1 := config.dataurl("text/plain", 42)
+	_, err2 := config.dataurlFile("logo.png")
+	_, err3 := config.dataurlSrcset(png)
+	_, err4 := config.dataurlSrcset(png, "1x, 2x")
+
+	for testNumber, err := range []error{err0, err1, err2, err3, err4} {
+		if _, ok := err.(BadRequestComplainer); !ok {
+			t.Errorf("For test #%d, expected the error to be a BadRequestComplainer, but actually was %T: %v", testNumber, err, err)
+			continue
+		}
+		if _, ok := err.(InternalErrorComplainer); ok {
+			t.Errorf("For test #%d, did not expect the error to be an InternalErrorComplainer, but actually was: %v", testNumber, err)
+			continue
+		}
+	}
+}
+
+
+func TestFuncMapTextTemplate(t *testing.T) {
+	tmpl := texttemplate.Must(texttemplate.New("test").Funcs(texttemplate.FuncMap(FuncMap())).Parse(`{{dataurl "text/css" .}}`))
+
+	var buffer strings.Builder
+	if err := tmpl.Execute(&buffer, "a{b:c}"); nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if expected, actual := `data:text/css,a%7Bb:c%7D`, buffer.String(); expected != actual {
+		t.Errorf("Expected %q, but actually got %q.", expected, actual)
+	}
+}
@@ -0,0 +1,68 @@
+package dataurl
+
+
+import (
+	"fmt"
+)
+
+
+// TooCompressedComplainer is used to represent a specific kind of BadRequestComplainer error.
+// Specifically, it represents compressed content that decompresses to more than some number of
+// times its (compressed) size. For example, a "zip bomb".
+//
+// (Content that decompresses to more than some number of bytes is a TooLargeComplainer.)
+type TooCompressedComplainer interface {
+	BadRequestComplainer
+	TooCompressedComplainer()
+	MaxRatio() int64
+}
+
+
+// internalTooCompressedComplainer is the only underlying implementation that fits the
+// TooCompressedComplainer interface, in this library.
+type internalTooCompressedComplainer struct {
+	msg      string
+	maxRatio int64
+}
+
+
+// newTooCompressedComplainer creates a new internalTooCompressedComplainer (struct) and
+// returns it as a TooCompressedComplainer (interface).
+func newTooCompressedComplainer(maxRatio int64, format string, a ...interface{}) TooCompressedComplainer {
+	msg := fmt.Sprintf(format, a...)
+
+	err := internalTooCompressedComplainer{
+		msg:msg,
+		maxRatio:maxRatio,
+	}
+
+	return &err
+}
+
+
+// Error method is necessary to satisfy the 'error' interface (and the
+// TooCompressedComplainer interface).
+func (err *internalTooCompressedComplainer) Error() string {
+	return fmt.Sprintf("Bad Request: Too Compressed: %s", err.msg)
+}
+
+
+// BadRequestComplainer method is necessary to satisfy the 'BadRequestComplainer' interface.
+// It exists to make this error type detectable in a Go type-switch.
+func (err *internalTooCompressedComplainer) BadRequestComplainer() {
+	// Nothing here.
+}
+
+
+// TooCompressedComplainer method is necessary to satisfy the 'TooCompressedComplainer' interface.
+// It exists to make this error type detectable in a Go type-switch.
+func (err *internalTooCompressedComplainer) TooCompressedComplainer() {
+	// Nothing here.
+}
+
+
+// MaxRatio returns the most the decompressed size was allowed to be, relative to the
+// compressed size, that was exceeded.
+func (err *internalTooCompressedComplainer) MaxRatio() int64 {
+	return err.maxRatio
+}
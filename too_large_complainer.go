@@ -0,0 +1,70 @@
+package dataurl
+
+
+import (
+	"fmt"
+)
+
+
+// TooLargeComplainer is used to represent a specific kind of BadRequestComplainer error.
+// Specifically, it represents content that is larger than some limit. For example, a
+// compressed data URL that would decompress to more than is allowed.
+//
+// Limit returns that limit, in bytes.
+//
+// (Compressed content that decompresses to more than some number of times its size is a
+// TooCompressedComplainer.)
+type TooLargeComplainer interface {
+	BadRequestComplainer
+	TooLargeComplainer()
+	Limit() int64
+}
+
+
+// internalTooLargeComplainer is the only underlying implementation that fits the
+// TooLargeComplainer interface, in this library.
+type internalTooLargeComplainer struct {
+	msg   string
+	limit int64
+}
+
+
+// newTooLargeComplainer creates a new internalTooLargeComplainer (struct) and
+// returns it as a TooLargeComplainer (interface).
+func newTooLargeComplainer(limit int64, format string, a ...interface{}) TooLargeComplainer {
+	msg := fmt.Sprintf(format, a...)
+
+	err := internalTooLargeComplainer{
+		msg:msg,
+		limit:limit,
+	}
+
+	return &err
+}
+
+
+// Error method is necessary to satisfy the 'error' interface (and the
+// TooLargeComplainer interface).
+func (err *internalTooLargeComplainer) Error() string {
+	return fmt.Sprintf("Bad Request: Too Large: %s", err.msg)
+}
+
+
+// BadRequestComplainer method is necessary to satisfy the 'BadRequestComplainer' interface.
+// It exists to make this error type detectable in a Go type-switch.
+func (err *internalTooLargeComplainer) BadRequestComplainer() {
+	// Nothing here.
+}
+
+
+// TooLargeComplainer method is necessary to satisfy the 'TooLargeComplainer' interface.
+// It exists to make this error type detectable in a Go type-switch.
+func (err *internalTooLargeComplainer) TooLargeComplainer() {
+	// Nothing here.
+}
+
+
+// Limit returns the limit (in bytes) that was exceeded.
+func (err *internalTooLargeComplainer) Limit() int64 {
+	return err.limit
+}
@@ -0,0 +1,37 @@
+/*
+Package xmlurl finds (and rewrites) the data URLs in SVG images, and other XML documents.
+
+It uses encoding/xml to go through the document. Data URLs are found in:
+
+	the values of attributes, in any namespace (ex: href, xlink:href)
+	the CSS url()s in attributes (ex: style) and in character data, including CDATA sections (ex: a <style> element)
+
+Each is reported with the path of the element it is in (ex: "/svg[1]/defs[1]/image[2]"), and the
+attribute (if any) it is in.
+
+Data URLs that are themselves XML documents (ex: an SVG image inside an SVG image) are scanned
+too; recursively. The data URLs found inside them are reported with the data URL they were found
+in as their parent.
+
+Example Usage
+
+	err := xmlurl.Walk(file, func(occurrence xmlurl.Occurrence) error {
+		fmt.Printf("%s: %s\n", occurrence, occurrence.Parcel.MediaType())
+		return nil
+	})
+	if nil != err {
+		//@TODO
+	}
+
+Another Example Usage
+
+	// Remove every data URL that is not a PNG image.
+	err := xmlurl.Rewrite(os.Stdout, file, func(occurrence xmlurl.Occurrence) (string, error) {
+		if !strings.HasPrefix(occurrence.Parcel.MediaType(), "image/png") {
+			return "", nil
+		}
+
+		return occurrence.DataURL, nil
+	})
+*/
+package xmlurl
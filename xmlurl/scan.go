@@ -0,0 +1,418 @@
+package xmlurl
+
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/reiver/go-dataurl"
+	"github.com/reiver/go-dataurl/internal/embedded"
+)
+
+
+// maxDepth is how deep data URLs (that are XML documents) in data URLs are scanned.
+const maxDepth = 8
+
+
+var (
+	errNilFunc = errors.New("xmlurl: nil func")
+)
+
+
+// Occurrence is a data URL found in an XML document.
+type Occurrence struct {
+	// Path is the path of the element that the data URL is in. Each step is the local name of an
+	// element, and its position (starting at 1) among its siblings with the same local name.
+	//
+	// For example: "/svg[1]/defs[1]/image[2]"
+	Path string
+
+	// Attribute is the name of the attribute that the data URL is in. If the data URL is in the
+	// character data (text, or a CDATA section) of the element, then it is the zero xml.Name.
+	Attribute xml.Name
+
+	DataURL string
+	Parcel  dataurl.Parcel
+
+	// Parent is the data URL (which is itself an XML document) that this data URL was found in.
+	// It is nil for data URLs found in the document passed to xmlurl.Walk() or xmlurl.Rewrite().
+	Parent *Occurrence
+}
+
+
+// String returns where the data URL is, in a form like an XPath. Namespaced attributes are written
+// in Clark notation. Each parent comes first, separated by " → ".
+//
+// For example:
+//
+//	/svg[1]/image[1]/@{http://www.w3.org/1999/xlink}href → /svg[1]/style[1]/text()
+func (occurrence Occurrence) String() string {
+	var step string
+	switch {
+	case "" == occurrence.Attribute.Local:
+		step = "/text()"
+	case "" == occurrence.Attribute.Space:
+		step = "/@" + occurrence.Attribute.Local
+	default:
+		step = "/@{" + occurrence.Attribute.Space + "}" + occurrence.Attribute.Local
+	}
+
+	if nil == occurrence.Parent {
+		return occurrence.Path + step
+	}
+
+	return occurrence.Parent.String() + " → " + occurrence.Path + step
+}
+
+
+// Walk reads an XML document from 'r', and calls 'fn' with each data URL in it.
+//
+// Data URLs in a data URL (that is an XML document) are passed to 'fn' before the data URL they
+// are in.
+//
+// If 'fn' returns an error, then Walk stops, and returns that error. An error is also returned if
+// the document (or an XML document in a data URL in it) is not well-formed.
+func Walk(r io.Reader, fn func(occurrence Occurrence) error) error {
+	if nil == fn {
+		return errNilFunc
+	}
+
+	return Rewrite(io.Discard, r, func(occurrence Occurrence) (string, error) {
+		return occurrence.DataURL, fn(occurrence)
+	})
+}
+
+
+// Rewrite reads an XML document from 'r', and writes it to 'w'; replacing each data URL in it with
+// what 'fn' returns for it. (To keep a data URL as it is, 'fn' returns Occurrence.DataURL.)
+//
+// Only what is replaced is changed. Everything else is written exactly as it was read. (Although
+// an attribute value, or text, that has something replaced in it is re-escaped.)
+//
+// Data URLs in a data URL (that is an XML document) are passed to 'fn' before the data URL they are
+// in. If any of them are replaced, then the data URL they are in is re-encoded (with the same media
+// type, and encoding); and that is what is passed to 'fn', as Occurrence.DataURL and Occurrence.Parcel.
+//
+// If 'fn' returns an error, then Rewrite stops, and returns that error. An error is also returned if
+// the document (or an XML document in a data URL in it) is not well-formed.
+func Rewrite(w io.Writer, r io.Reader, fn func(occurrence Occurrence) (string, error)) error {
+	if nil == fn {
+		return errNilFunc
+	}
+
+	document, err := io.ReadAll(r)
+	if nil != err {
+		return err
+	}
+
+	rewritten, err := scanner{fn}.scan(document, nil, 0)
+	if nil != err {
+		return err
+	}
+
+	_, err = w.Write(rewritten)
+	return err
+}
+
+
+type scanner struct {
+	fn func(Occurrence) (string, error)
+}
+
+
+// element is an element that scan() is inside of.
+type element struct {
+	path   string
+	counts map[string]int // Local name → how many children with that name there have been.
+}
+
+
+// scan returns 'document' with each data URL in it replaced. 'parent' is the data URL (if any) that
+// 'document' is the contents of.
+func (s scanner) scan(document []byte, parent *Occurrence, depth int) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(document))
+
+	var buffer bytes.Buffer
+
+	stack := []*element{{counts: map[string]int{}}}
+
+	var previous int64
+	for {
+		token, err := decoder.Token()
+		if io.EOF == err {
+			break
+		}
+		if nil != err {
+			return nil, fmt.Errorf("xmlurl: %w", err)
+		}
+
+		offset := decoder.InputOffset()
+		raw := document[previous:offset]
+		previous = offset
+
+		top := stack[len(stack)-1]
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			top.counts[t.Name.Local]++
+			e := element{
+				path:   fmt.Sprintf("%s/%s[%d]", top.path, t.Name.Local, top.counts[t.Name.Local]),
+				counts: map[string]int{},
+			}
+			stack = append(stack, &e)
+
+			raw, err = s.startElement(raw, t, Occurrence{Path: e.path, Parent: parent}, depth)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			raw, err = s.charData(raw, t, Occurrence{Path: top.path, Parent: parent}, depth)
+		}
+		if nil != err {
+			return nil, err
+		}
+
+		buffer.Write(raw)
+	}
+	buffer.Write(document[previous:])
+
+	return buffer.Bytes(), nil
+}
+
+
+// startElement returns the start tag 'raw' (of the element 'start') with each data URL in its
+// attributes replaced.
+func (s scanner) startElement(raw []byte, start xml.StartElement, occurrence Occurrence, depth int) ([]byte, error) {
+	spans := attributeValueSpans(raw)
+	if len(start.Attr) != len(spans) {
+		return nil, fmt.Errorf("xmlurl: could not find the attributes of <%s> at %s", start.Name.Local, occurrence.Path)
+	}
+
+	var buffer bytes.Buffer
+
+	previous := 0
+	for i, attr := range start.Attr {
+		occurrence.Attribute = attr.Name
+
+		value, err := s.replaceIn(attr.Value, occurrence, depth)
+		if nil != err {
+			return nil, err
+		}
+		if attr.Value == value {
+			continue
+		}
+
+		buffer.Write(raw[previous:spans[i][0]])
+		if err := xml.EscapeText(&buffer, []byte(value)); nil != err {
+			return nil, err
+		}
+		previous = spans[i][1]
+	}
+	if 0 == previous {
+		return raw, nil
+	}
+	buffer.Write(raw[previous:])
+
+	return buffer.Bytes(), nil
+}
+
+
+// charData returns the character data 'raw' (which is 'text' before being unescaped) with each data
+// URL in it replaced.
+func (s scanner) charData(raw []byte, text xml.CharData, occurrence Occurrence, depth int) ([]byte, error) {
+	if 0 == len(bytes.TrimSpace(text)) {
+		return raw, nil
+	}
+
+	value, err := s.replaceIn(string(text), occurrence, depth)
+	if nil != err {
+		return nil, err
+	}
+	if string(text) == value {
+		return raw, nil
+	}
+
+	const cdataStart = "<![CDATA["
+	const cdataEnd = "]]>"
+	if bytes.HasPrefix(raw, []byte(cdataStart)) {
+		return []byte(cdataStart + strings.ReplaceAll(value, cdataEnd, "]]" + cdataEnd + cdataStart + ">") + cdataEnd), nil
+	}
+
+	var buffer bytes.Buffer
+	if err := xml.EscapeText(&buffer, []byte(value)); nil != err {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+
+// replaceIn returns 'value' (an attribute value, or character data) with each data URL in it replaced.
+//
+// If the whole of 'value' (ignoring whitespace around it) is a data URL, then that is used; since
+// a data URL in an attribute can have spaces, quotes, etc in it (ex: an unencoded SVG image).
+// Otherwise, 'value' is taken to be CSS (ex: a style attribute, or a <style> element); and the data
+// URLs in its url()s are used.
+func (s scanner) replaceIn(value string, occurrence Occurrence, depth int) (string, error) {
+	if trimmed := strings.TrimSpace(value); embedded.HasScheme(trimmed, "data:") {
+		replacement, ok, err := s.replace(trimmed, occurrence, depth)
+		if nil != err {
+			return "", err
+		}
+		if ok {
+			return strings.Replace(value, trimmed, replacement, 1), nil
+		}
+	}
+
+	content := []byte(value)
+
+	var spans []embedded.Span
+	for _, match := range embedded.Find(content, embedded.SyntaxCSS) {
+		if !embedded.HasScheme(match.URL, "data:") {
+			continue
+		}
+
+		replacement, ok, err := s.replace(match.URL, occurrence, depth)
+		if nil != err {
+			return "", err
+		}
+		if !ok {
+			continue
+		}
+
+		spans = append(spans, embedded.Span{
+			Start: match.Start,
+			End:   match.End,
+			Text:  match.Escape(replacement),
+		})
+	}
+	if len(spans) < 1 {
+		return value, nil
+	}
+
+	return string(embedded.Replace(content, spans)), nil
+}
+
+
+// replace returns what 'dataURL' is to be replaced with. It returns false if 'dataURL' is not
+// actually a data URL.
+func (s scanner) replace(dataURL string, occurrence Occurrence, depth int) (string, bool, error) {
+	dataURL = strings.TrimSpace(dataURL)
+
+	parcel, err := embedded.Parse(dataURL)
+	if nil != err {
+		return "", false, nil
+	}
+
+	occurrence.DataURL = dataURL
+	occurrence.Parcel = parcel
+
+	if isXML(parcel.MediaType()) && depth < maxDepth {
+		inner := occurrence
+
+		rewritten, err := s.scan(dataurl.UnsafeBytes(parcel), &inner, depth+1)
+		if nil != err {
+			return "", false, fmt.Errorf("xmlurl: in the data URL at %s: %w", occurrence, err)
+		}
+
+		if !bytes.Equal(dataurl.UnsafeBytes(parcel), rewritten) {
+			mediaType, encoding := mediaTypeAndEncodingOf(dataURL)
+
+			occurrence.DataURL, err = dataurl.Encode(mediaType, rewritten, encoding)
+			if nil != err {
+				return "", false, err
+			}
+
+			occurrence.Parcel, err = dataurl.Parse(occurrence.DataURL)
+			if nil != err {
+				return "", false, err
+			}
+		}
+	}
+
+	replacement, err := s.fn(occurrence)
+	if nil != err {
+		return "", false, err
+	}
+
+	return replacement, true, nil
+}
+
+
+// attributeValueSpans returns where (in the start tag 'raw') the value of each attribute is; not
+// including the quotes around it. They are in the same order as the attributes are.
+func attributeValueSpans(raw []byte) [][2]int {
+	var spans [][2]int
+
+	isSpace := func(b byte) bool {
+		return ' ' == b || '\t' == b || '\r' == b || '\n' == b
+	}
+
+	// Skip over the "<" and the name of the element.
+	i := 1
+	for i < len(raw) && !isSpace(raw[i]) && '/' != raw[i] && '>' != raw[i] {
+		i++
+	}
+
+	for i < len(raw) {
+		for i < len(raw) && isSpace(raw[i]) {
+			i++
+		}
+		if len(raw) <= i || '/' == raw[i] || '>' == raw[i] {
+			break
+		}
+
+		// The name of the attribute, and the "=".
+		for i < len(raw) && '=' != raw[i] {
+			i++
+		}
+		i++
+		for i < len(raw) && isSpace(raw[i]) {
+			i++
+		}
+		if len(raw) <= i {
+			break
+		}
+
+		quote := raw[i]
+		start := i + 1
+		end := bytes.IndexByte(raw[start:], quote)
+		if -1 == end {
+			break
+		}
+		end += start
+
+		spans = append(spans, [2]int{start, end})
+		i = end + 1
+	}
+
+	return spans
+}
+
+
+// mediaTypeAndEncodingOf returns the media type (as it is written), and the encoding, of 'dataURL'.
+func mediaTypeAndEncodingOf(dataURL string) (string, dataurl.Encoding) {
+	mediaType, _, _ := strings.Cut(dataURL[len("data:"):], ",")
+
+	if trimmed := strings.TrimSuffix(mediaType, ";base64"); trimmed != mediaType {
+		return trimmed, dataurl.EncodingBase64
+	}
+
+	return mediaType, dataurl.EncodingPercent
+}
+
+
+// isXML returns whether the media type 'mediaType' is XML (ex: "image/svg+xml").
+func isXML(mediaType string) bool {
+	mimeType, _, err := mime.ParseMediaType(mediaType)
+	if nil != err {
+		return false
+	}
+
+	return "text/xml" == mimeType || "application/xml" == mimeType || strings.HasSuffix(mimeType, "+xml")
+}
+
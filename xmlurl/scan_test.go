@@ -0,0 +1,191 @@
+package xmlurl
+
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/reiver/go-dataurl"
+)
+
+
+const innerSVG = `<svg xmlns="http://www.w3.org/2000/svg"><image href="data:image/png;base64,iVBORw0KGgo="/></svg>`
+
+
+func document(t *testing.T, inner string) string {
+	innerDataURL, err := dataurl.Encode("image/svg+xml", []byte(inner), dataurl.EncodingPercent)
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	return `<?xml version="1.0"?>
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">
+  <style><![CDATA[
+    @font-face { src: url(data:font/woff2;base64,d09GMgAB) }
+  ]]></style>
+  <defs>
+    <image id="a" xlink:href="data:image/png;base64,iVBORw0KGgo="/>
+    <image id="b" href='` + innerDataURL + `'/>
+  </defs>
+  <rect style="fill: url(#g); background: url(&apos;data:image/gif;base64,R0lGODlh&apos;)"/>
+  <!-- data:,ignored -->
+  <text>not data:</text>
+</svg>
+`
+}
+
+
+func TestWalk(t *testing.T) {
+	var actual []string
+
+	err := Walk(strings.NewReader(document(t, innerSVG)), func(occurrence Occurrence) error {
+		actual = append(actual, occurrence.String() + " " + occurrence.Parcel.MediaType())
+		return nil
+	})
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	expected := []string{
+		`/svg[1]/style[1]/text() font/woff2;charset=US-ASCII`,
+		`/svg[1]/defs[1]/image[1]/@{http://www.w3.org/1999/xlink}href image/png;charset=US-ASCII`,
+		`/svg[1]/defs[1]/image[2]/@href → /svg[1]/image[1]/@href image/png;charset=US-ASCII`,
+		`/svg[1]/defs[1]/image[2]/@href image/svg+xml;charset=US-ASCII`,
+		`/svg[1]/rect[1]/@style image/gif;charset=US-ASCII`,
+	}
+
+	if len(expected) != len(actual) {
+		t.Fatalf("Expected %d data URLs, but actually got %d: %q", len(expected), len(actual), actual)
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			t.Errorf("For data URL #%d, expected %q, but actually got %q.", i, expected[i], actual[i])
+		}
+	}
+}
+
+
+func TestWalkQuotedNestedSVG(t *testing.T) {
+	const nested = `data:image/svg+xml,%3Csvg xmlns='http://www.w3.org/2000/svg'%3E%3C/svg%3E`
+
+	const doc = `<svg xmlns="http://www.w3.org/2000/svg">
+  <style>.a { background: url("` + nested + `") }</style>
+  <rect style="fill: url(&quot;` + nested + `&quot;)"/>
+</svg>`
+
+	var actual []string
+
+	err := Walk(strings.NewReader(doc), func(occurrence Occurrence) error {
+		actual = append(actual, occurrence.String() + " " + occurrence.DataURL)
+		return nil
+	})
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	expected := []string{
+		`/svg[1]/style[1]/text() ` + nested,
+		`/svg[1]/rect[1]/@style ` + nested,
+	}
+
+	if len(expected) != len(actual) {
+		t.Fatalf("Expected %d data URLs, but actually got %d: %q", len(expected), len(actual), actual)
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			t.Errorf("For data URL #%d, expected %q, but actually got %q.", i, expected[i], actual[i])
+		}
+	}
+
+	var buffer bytes.Buffer
+	err = Rewrite(&buffer, strings.NewReader(doc), func(occurrence Occurrence) (string, error) {
+		return "data:,a b", nil
+	})
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if expected, actual := `<svg xmlns="http://www.w3.org/2000/svg">
+  <style>.a { background: url(&#34;data:,a\20 b&#34;) }</style>
+  <rect style="fill: url(&#34;data:,a\20 b&#34;)"/>
+</svg>`, buffer.String(); expected != actual {
+		t.Errorf("Expected:\n%s\nbut actually got:\n%s", expected, actual)
+	}
+}
+
+
+func TestRewrite(t *testing.T) {
+	var buffer bytes.Buffer
+
+	err := Rewrite(&buffer, strings.NewReader(document(t, innerSVG)), func(occurrence Occurrence) (string, error) {
+		switch {
+		case strings.HasPrefix(occurrence.Parcel.MediaType(), "font/"):
+			return "", nil
+		case strings.HasPrefix(occurrence.Parcel.MediaType(), "image/png") && nil != occurrence.Parent:
+			return "data:image/png;base64,AAAA", nil
+		case strings.HasPrefix(occurrence.Parcel.MediaType(), "image/gif"):
+			return "data:image/gif;base64,R0lGODdh", nil
+		default:
+			return occurrence.DataURL, nil
+		}
+	})
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	expected := document(t, strings.Replace(innerSVG, "iVBORw0KGgo=", "AAAA", 1))
+	expected = strings.Replace(expected, "url(data:font/woff2;base64,d09GMgAB)", "url()", 1)
+	expected = strings.Replace(expected,
+		`style="fill: url(#g); background: url(&apos;data:image/gif;base64,R0lGODlh&apos;)"`,
+		`style="fill: url(#g); background: url(&#39;data:image/gif;base64,R0lGODdh&#39;)"`, 1)
+
+	if actual := buffer.String(); expected != actual {
+		t.Errorf("Expected:\n%s\nbut actually got:\n%s", expected, actual)
+	}
+}
+
+
+func TestRewriteKeepAll(t *testing.T) {
+	var buffer bytes.Buffer
+
+	original := document(t, innerSVG)
+
+	err := Rewrite(&buffer, strings.NewReader(original), func(occurrence Occurrence) (string, error) {
+		return occurrence.DataURL, nil
+	})
+	if nil != err {
+		t.Fatalf("Did not expect an error, but actually got one: %v", err)
+	}
+
+	if expected, actual := original, buffer.String(); expected != actual {
+		t.Errorf("Expected:\n%s\nbut actually got:\n%s", expected, actual)
+	}
+}
+
+
+func TestWalkErrors(t *testing.T) {
+	stop := errors.New("stop")
+
+	err := Walk(strings.NewReader(document(t, innerSVG)), func(Occurrence) error {
+		return stop
+	})
+	if stop != err {
+		t.Errorf("Expected the error from the func, but actually got: %v", err)
+	}
+
+	err = Walk(strings.NewReader(document(t, `<svg><image></svg>`)), func(Occurrence) error {
+		return nil
+	})
+	if nil == err {
+		t.Errorf("Expected an error for a data URL that is not well-formed XML, but did not actually get one.")
+	}
+
+	err = Walk(strings.NewReader(`<svg>`), func(Occurrence) error {
+		return nil
+	})
+	if nil == err {
+		t.Errorf("Expected an error for a document that is not well-formed XML, but did not actually get one.")
+	}
+}